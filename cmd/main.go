@@ -6,9 +6,13 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/ivuorinen/go-test-sarif-action/internal"
+	"github.com/ivuorinen/go-test-sarif-action/internal/recognize"
 	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
 )
 
@@ -33,12 +37,48 @@ func printVersion(w io.Writer) {
 
 func printUsage(w io.Writer) {
 	_, _ = fmt.Fprintln(w, "Usage: go-test-sarif [options] <input.json> <output.sarif>")
+	_, _ = fmt.Fprintln(w, "       go-test-sarif [options] -o <output.sarif> <input.json|glob|->...")
 	_, _ = fmt.Fprintln(w, "       go-test-sarif --version")
 	_, _ = fmt.Fprintln(w, "")
+	_, _ = fmt.Fprintln(w, "Inputs hold go test -json, go build -json or go vet -json output,")
+	_, _ = fmt.Fprintln(w, "Ginkgo JSON reports or go test -coverprofile coverage profiles. A")
+	_, _ = fmt.Fprintln(w, "directory is read as a bazel-testlogs tree.")
+	_, _ = fmt.Fprintln(w, "")
 	_, _ = fmt.Fprintln(w, "Options:")
 	_, _ = fmt.Fprintf(w, "  --sarif-version string   SARIF version (%s) (default %q)\n",
 		strings.Join(sarif.SupportedVersions(), ", "), sarif.DefaultVersion)
+	_, _ = fmt.Fprintln(w, "  -o, --output string      Output file; all arguments are then inputs")
+	_, _ = fmt.Fprintln(w, "  --package [file=]pkg     Package for events without one (repeatable)")
+	_, _ = fmt.Fprintln(w, "  --attr-level key         Test attribute that sets the result level")
+	_, _ = fmt.Fprintln(w, "  --attr-tags key          Test attribute with comma-separated rule tags")
+	_, _ = fmt.Fprintln(w, "  --attr-owners key        Test attribute with comma-separated owners")
+	_, _ = fmt.Fprintln(w, "  --attr-help-uri key      Test attribute with the rule help or issue link")
+	_, _ = fmt.Fprintln(w, "  --source-root dir        Source tree that file locations are resolved against")
+	_, _ = fmt.Fprintln(w, "  --artifact-dir pattern   Artifact directory of failing tests; {package}")
+	_, _ = fmt.Fprintln(w, "                           and {test} are replaced per test")
+	_, _ = fmt.Fprintln(w, "  --golden-pattern regexp  Output line reporting a golden-file mismatch, with")
+	_, _ = fmt.Fprintln(w, "                           a (?P<file>...) group for the file (repeatable)")
+	_, _ = fmt.Fprintln(w, "  --slow-threshold dur     Report tests slower than dur, e.g. 2s")
+	_, _ = fmt.Fprintln(w, "  --slow-top n             Report the n slowest tests")
+	_, _ = fmt.Fprintln(w, "  --slow-config file       JSON file with slow test thresholds per package")
+	_, _ = fmt.Fprintln(w, "                           or test; flags take precedence")
+	_, _ = fmt.Fprintln(w, "  --bench-text file        Write benchmark results for benchstat to file")
+	_, _ = fmt.Fprintln(w, "  --bench-json file        Write benchmark results as JSON to file")
+	_, _ = fmt.Fprintln(w, "  --bench-baseline file    Report benchmark regressions against the results")
+	_, _ = fmt.Fprintln(w, "                           in file (--bench-json, go test -json or benchstat)")
+	_, _ = fmt.Fprintln(w, "  --bench-threshold pct    Change reported as a regression (default 5)")
+	_, _ = fmt.Fprintln(w, "  --bench-alpha p          Significance level of regressions (default 0.05)")
+	_, _ = fmt.Fprintln(w, "  --coverage-summary file  Write the coverage of each package as JSON to file")
+	_, _ = fmt.Fprintln(w, "  --min-file-coverage pct  Report files covering less than pct% of statements")
+	_, _ = fmt.Fprintln(w, "  --min-coverage, --min-package-coverage pct")
+	_, _ = fmt.Fprintln(w, "                           Report packages covering under pct% of statements,")
+	_, _ = fmt.Fprintln(w, "                           from the profiles or else the go test -cover output")
+	_, _ = fmt.Fprintln(w, "  --coverage-diff file     Report lines added by the unified diff in file")
+	_, _ = fmt.Fprintln(w, "                           that are not covered, e.g. git diff main...HEAD")
+	_, _ = fmt.Fprintln(w, "  --rerun file             Write commands rerunning the failed tests to file,")
+	_, _ = fmt.Fprintln(w, "                           as JSON if it ends in .json, else a shell script")
 	_, _ = fmt.Fprintln(w, "  --pretty                 Pretty-print JSON output")
+	_, _ = fmt.Fprintln(w, "  --compress               Gzip the output (implied by a .gz output name)")
 	_, _ = fmt.Fprintln(w, "  -v, --version            Display version information")
 }
 
@@ -50,6 +90,21 @@ func run(args []string, stdout, stderr io.Writer) int {
 		versionFlag  bool
 		sarifVersion string
 		prettyOutput bool
+		outputFile   string
+		compressOut  bool
+		packages     packageFlag
+		attrs        internal.AttrMapping
+		artifactDir  string
+		sourceRoot   string
+		golden       goldenFlag
+		slowConfig   string
+		slowLimit    time.Duration
+		slowTop      int
+		benchText    string
+		benchJSON    string
+		baseline     internal.BenchmarkBaseline
+		cover        internal.CoverageOptions
+		rerun        string
 	)
 
 	fs.BoolVar(&versionFlag, "version", false, "Display version information")
@@ -57,6 +112,31 @@ func run(args []string, stdout, stderr io.Writer) int {
 	fs.StringVar(&sarifVersion, "sarif-version", string(sarif.DefaultVersion),
 		fmt.Sprintf("SARIF version (%s)", strings.Join(sarif.SupportedVersions(), ", ")))
 	fs.BoolVar(&prettyOutput, "pretty", false, "Pretty-print JSON output")
+	fs.BoolVar(&compressOut, "compress", false, "Gzip the output")
+	fs.StringVar(&attrs.LevelKey, "attr-level", "", "Test attribute that sets the result level")
+	fs.StringVar(&attrs.TagsKey, "attr-tags", "", "Test attribute with comma-separated rule tags")
+	fs.StringVar(&attrs.OwnersKey, "attr-owners", "", "Test attribute with comma-separated owners")
+	fs.StringVar(&attrs.HelpURIKey, "attr-help-uri", "", "Test attribute with the rule help or issue link")
+	fs.StringVar(&sourceRoot, "source-root", "", "Source tree that file locations are resolved against")
+	fs.StringVar(&artifactDir, "artifact-dir", "", "Artifact directory pattern of failing tests")
+	fs.Var(&packages, "package", "Package for events without one, optionally as file=package")
+	fs.Var(&golden, "golden-pattern", "Output line reporting a golden-file mismatch")
+	fs.DurationVar(&slowLimit, "slow-threshold", 0, "Report tests slower than this")
+	fs.IntVar(&slowTop, "slow-top", 0, "Report this many of the slowest tests")
+	fs.StringVar(&slowConfig, "slow-config", "", "JSON file with slow test thresholds")
+	fs.StringVar(&benchText, "bench-text", "", "File to write benchmark results for benchstat to")
+	fs.StringVar(&benchJSON, "bench-json", "", "File to write benchmark results as JSON to")
+	fs.StringVar(&baseline.File, "bench-baseline", "", "Baseline benchmark results to compare against")
+	fs.Float64Var(&baseline.Threshold, "bench-threshold", 5, "Change in percent reported as a regression")
+	fs.Float64Var(&baseline.Alpha, "bench-alpha", 0.05, "Significance level of benchmark regressions")
+	fs.StringVar(&cover.Summary, "coverage-summary", "", "File to write the coverage of each package as JSON to")
+	fs.Float64Var(&cover.FileThreshold, "min-file-coverage", 0, "Statement coverage in percent each file must reach")
+	fs.Float64Var(&cover.PackageThreshold, "min-package-coverage", 0, "Statement coverage in percent each package must reach")
+	fs.Float64Var(&cover.PackageThreshold, "min-coverage", 0, "Statement coverage in percent each package must reach (short)")
+	fs.StringVar(&cover.Diff, "coverage-diff", "", "Unified diff whose uncovered added lines are reported")
+	fs.StringVar(&rerun, "rerun", "", "File to write commands rerunning the failed tests to")
+	fs.StringVar(&outputFile, "output", "", "Output file")
+	fs.StringVar(&outputFile, "o", "", "Output file (short)")
 
 	if err := fs.Parse(args[1:]); err != nil {
 		return 1
@@ -67,20 +147,58 @@ func run(args []string, stdout, stderr io.Writer) int {
 		return 0
 	}
 
-	if fs.NArg() < 2 {
+	patterns := fs.Args()
+	if outputFile == "" {
+		if len(patterns) < 2 {
+			printUsage(stderr)
+			return 1
+		}
+		outputFile = patterns[len(patterns)-1]
+		patterns = patterns[:len(patterns)-1]
+	}
+	if len(patterns) == 0 {
 		printUsage(stderr)
 		return 1
 	}
 
-	inputFile := fs.Arg(0)
-	outputFile := fs.Arg(1)
+	inputFiles, err := expandInputs(patterns)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
 
 	opts := internal.ConvertOptions{
-		SARIFVersion: sarif.Version(sarifVersion),
-		Pretty:       prettyOutput,
+		SARIFVersion:       sarif.Version(sarifVersion),
+		Pretty:             prettyOutput,
+		Compress:           compressOut,
+		Package:            packages.pkg,
+		PackageByInput:     packages.byInput,
+		Attributes:         attrs,
+		SourceRoot:         sourceRoot,
+		ArtifactDirPattern: artifactDir,
+		BenchmarkText:      benchText,
+		BenchmarkJSON:      benchJSON,
+		Baseline:           baseline,
+		Coverage:           cover,
+		Rerun:              rerun,
+	}
+	if len(golden) > 0 {
+		opts.Recognizers = recognize.WithGoldenPatterns(recognize.Default(), golden...)
+	}
+	if slowConfig != "" {
+		if opts.Slow, err = internal.LoadSlowOptions(slowConfig); err != nil {
+			_, _ = fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+	if slowLimit > 0 {
+		opts.Slow.Threshold = slowLimit
+	}
+	if slowTop > 0 {
+		opts.Slow.Top = slowTop
 	}
 
-	if err := internal.ConvertToSARIF(inputFile, outputFile, opts); err != nil {
+	if err := internal.ConvertFilesToSARIF(inputFiles, outputFile, opts); err != nil {
 		_, _ = fmt.Fprintf(stderr, "Error: %v\n", err)
 		return 1
 	}
@@ -88,6 +206,93 @@ func run(args []string, stdout, stderr io.Writer) int {
 	return 0
 }
 
+// packageFlag collects --package values: a bare package applies to every
+// input, while file=package applies to a single input.
+type packageFlag struct {
+	pkg     string
+	byInput map[string]string
+}
+
+func (p *packageFlag) String() string {
+	if p == nil {
+		return ""
+	}
+	return p.pkg
+}
+
+func (p *packageFlag) Set(value string) error {
+	file, pkg, ok := strings.Cut(value, "=")
+	if !ok {
+		p.pkg = value
+		return nil
+	}
+	if file == "" || pkg == "" {
+		return fmt.Errorf("invalid package mapping %q, want file=package", value)
+	}
+	if p.byInput == nil {
+		p.byInput = make(map[string]string)
+	}
+	p.byInput[file] = pkg
+	return nil
+}
+
+// goldenFlag collects --golden-pattern values: regular expressions
+// matching the line a golden-file helper prints on a mismatch, with a
+// "file" group capturing the golden file.
+type goldenFlag []recognize.GoldenPattern
+
+func (g *goldenFlag) String() string {
+	if g == nil {
+		return ""
+	}
+	patterns := make([]string, len(*g))
+	for i, p := range *g {
+		patterns[i] = p.Mismatch.String()
+	}
+	return strings.Join(patterns, ", ")
+}
+
+func (g *goldenFlag) Set(value string) error {
+	re, err := regexp.Compile(value)
+	if err != nil {
+		return err
+	}
+	if re.SubexpIndex("file") < 0 {
+		return fmt.Errorf("golden pattern %q has no (?P<file>...) group", value)
+	}
+	*g = append(*g, recognize.GoldenPattern{Mismatch: re})
+	return nil
+}
+
+// expandInputs resolves glob patterns into input file paths. Arguments
+// without glob metacharacters, including "-" for standard input, are kept
+// as-is so a missing file is reported by the parser. Paths matched by more
+// than one pattern are kept once.
+func expandInputs(patterns []string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		matches := []string{pattern}
+		if strings.ContainsAny(pattern, "*?[") {
+			var err error
+			matches, err = filepath.Glob(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no input files match %q", pattern)
+			}
+		}
+		for _, m := range matches {
+			if !seen[m] {
+				seen[m] = true
+				files = append(files, m)
+			}
+		}
+	}
+	return files, nil
+}
+
 func main() {
 	os.Exit(run(os.Args, os.Stdout, os.Stderr))
 }
@@ -120,6 +120,81 @@ func TestRun(t *testing.T) {
 			setupFunc: setupValidTestFiles,
 			wantExit:  0,
 		},
+		{
+			name:      "with output flag",
+			args:      []string{testutil.AppName, "-o", testutil.OutputSARIF, testutil.InputJSON},
+			setupFunc: setupValidTestFiles,
+			wantExit:  0,
+		},
+		{
+			name:      "with compress flag",
+			args:      []string{testutil.AppName, "--compress", testutil.InputJSON, testutil.OutputSARIF},
+			setupFunc: setupValidTestFiles,
+			wantExit:  0,
+		},
+		{
+			name:      "with package flag",
+			args:      []string{testutil.AppName, "--package", "example.com/test", testutil.InputJSON, testutil.OutputSARIF},
+			setupFunc: setupValidTestFiles,
+			wantExit:  0,
+		},
+		{
+			name: "with attribute flags",
+			args: []string{testutil.AppName, "--attr-level", "severity", "--attr-tags", "tags",
+				"--attr-owners", "owner", "--attr-help-uri", "issue", testutil.InputJSON, testutil.OutputSARIF},
+			setupFunc: setupValidTestFiles,
+			wantExit:  0,
+		},
+		{
+			name:      "with source-root flag",
+			args:      []string{testutil.AppName, "--source-root", ".", testutil.InputJSON, testutil.OutputSARIF},
+			setupFunc: setupValidTestFiles,
+			wantExit:  0,
+		},
+		{
+			name:      "with artifact-dir flag",
+			args:      []string{testutil.AppName, "--artifact-dir", "artifacts/{test}", testutil.InputJSON, testutil.OutputSARIF},
+			setupFunc: setupValidTestFiles,
+			wantExit:  0,
+		},
+		{
+			name:      "with slow test flags",
+			args:      []string{testutil.AppName, "--slow-threshold", "2s", "--slow-top", "5", testutil.InputJSON, testutil.OutputSARIF},
+			setupFunc: setupValidTestFiles,
+			wantExit:  0,
+		},
+		{
+			name:       "missing benchmark baseline",
+			args:       []string{testutil.AppName, "--bench-baseline", "nonexistent.json", testutil.InputJSON, testutil.OutputSARIF},
+			setupFunc:  setupValidTestFiles,
+			wantExit:   1,
+			wantStderr: "Error:",
+		},
+		{
+			name:       "missing slow config",
+			args:       []string{testutil.AppName, "--slow-config", "nonexistent.json", testutil.InputJSON, testutil.OutputSARIF},
+			setupFunc:  setupValidTestFiles,
+			wantExit:   1,
+			wantStderr: "Error:",
+		},
+		{
+			name:       "invalid package mapping",
+			args:       []string{testutil.AppName, "--package", "=example.com/test", testutil.InputJSON, testutil.OutputSARIF},
+			wantExit:   1,
+			wantStderr: "invalid package mapping",
+		},
+		{
+			name:       "output flag without inputs",
+			args:       []string{testutil.AppName, "-o", testutil.OutputSARIF},
+			wantExit:   1,
+			wantStderr: "Usage: " + testutil.AppName,
+		},
+		{
+			name:       "glob without matches",
+			args:       []string{testutil.AppName, "-o", testutil.OutputSARIF, "nonexistent-*.json"},
+			wantExit:   1,
+			wantStderr: "no input files match",
+		},
 		{
 			name:       "invalid sarif version",
 			args:       []string{testutil.AppName, "--sarif-version", "9.9.9", testutil.InputJSON, testutil.OutputSARIF},
@@ -136,6 +211,183 @@ func TestRun(t *testing.T) {
 	}
 }
 
+func TestRun_MultipleInputs(t *testing.T) {
+	dir := t.TempDir()
+	shard := `{"Action":"fail","Package":"example.com/foo","Test":"TestBar","Output":"failed"}` + "\n"
+	for _, name := range []string{"shard-1.json", "shard-2.json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(shard), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	outputFile := filepath.Join(dir, testutil.OutputSARIF)
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	args := []string{testutil.AppName, "-o", outputFile, filepath.Join(dir, "shard-*.json")}
+	if code := run(args, stdout, stderr); code != 0 {
+		t.Fatalf("exit code = %d, stderr = %q", code, stderr.String())
+	}
+
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if got := strings.Count(string(data), `"ruleId"`); got != 1 {
+		t.Errorf("got %d results, want 1 merged result", got)
+	}
+	if !strings.Contains(string(data), "shard-2.json") {
+		t.Errorf("output does not record shard-2.json as an input: %s", data)
+	}
+}
+
+func TestRun_BenchmarkOutputs(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "bench.json")
+	events := `{"Action":"output","Package":"example.com/foo","Test":"BenchmarkA","Output":"BenchmarkA \t 10\t 5 ns/op\n"}` + "\n"
+	if err := os.WriteFile(input, []byte(events), 0o600); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	text := filepath.Join(dir, "bench.txt")
+	doc := filepath.Join(dir, "bench-results.json")
+
+	args := []string{testutil.AppName, "--bench-text", text, "--bench-json", doc, input, filepath.Join(dir, "out.sarif")}
+	if code := run(args, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("exit code = %d, want 0", code)
+	}
+	for _, file := range []string{text, doc} {
+		data, err := os.ReadFile(file)
+		if err != nil || !strings.Contains(string(data), "BenchmarkA") {
+			t.Errorf("%s = %q, %v, want the benchmark", file, data, err)
+		}
+	}
+}
+
+func TestRun_Coverage(t *testing.T) {
+	dir := t.TempDir()
+	profile := filepath.Join(dir, "cover.out")
+	data := "mode: set\nexample.com/foo/x.go:3.14,5.2 1 1\nexample.com/foo/x.go:7.14,9.2 3 0\n"
+	if err := os.WriteFile(profile, []byte(data), 0o600); err != nil {
+		t.Fatalf("failed to write profile: %v", err)
+	}
+	output := filepath.Join(dir, "out.sarif")
+
+	args := []string{testutil.AppName, "--min-file-coverage", "50", "--min-package-coverage", "20", profile, output}
+	if code := run(args, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("exit code = %d, want 0", code)
+	}
+	report, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if got := strings.Count(string(report), `"ruleId":"go-coverage-low"`); got != 1 {
+		t.Errorf("got %d coverage results, want the file only:\n%s", got, report)
+	}
+
+	args = []string{testutil.AppName, "--coverage-diff", filepath.Join(dir, "missing.diff"), profile, output}
+	var stderr bytes.Buffer
+	if code := run(args, &bytes.Buffer{}, &stderr); code != 1 || !strings.Contains(stderr.String(), "Error:") {
+		t.Errorf("exit code = %d, stderr = %q, want an error for a missing diff", code, stderr.String())
+	}
+}
+
+func TestRun_CoverageSummary(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "test.json")
+	events := `{"Action":"output","Package":"example.com/foo","Output":"coverage: 42.0% of statements\n"}` + "\n" +
+		`{"Action":"pass","Package":"example.com/foo"}` + "\n"
+	if err := os.WriteFile(input, []byte(events), 0o600); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	summary := filepath.Join(dir, "coverage.json")
+	output := filepath.Join(dir, "out.sarif")
+
+	args := []string{testutil.AppName, "--min-coverage", "50", "--coverage-summary", summary, input, output}
+	if code := run(args, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("exit code = %d, want 0", code)
+	}
+	data, err := os.ReadFile(summary)
+	if err != nil || !strings.Contains(string(data), `"coverage": 42`) {
+		t.Errorf("summary = %q, %v, want the package coverage", data, err)
+	}
+	report, err := os.ReadFile(output)
+	if err != nil || !strings.Contains(string(report), `"ruleId":"go-coverage-low"`) {
+		t.Errorf("report = %q, %v, want a coverage result", report, err)
+	}
+}
+
+func TestRun_Rerun(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "test.json")
+	events := `{"Action":"run","Package":"example.com/foo","Test":"TestA"}` + "\n" +
+		`{"Action":"fail","Package":"example.com/foo","Test":"TestA"}` + "\n"
+	if err := os.WriteFile(input, []byte(events), 0o600); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	script := filepath.Join(dir, "rerun.sh")
+
+	args := []string{testutil.AppName, "--rerun", script, input, filepath.Join(dir, "out.sarif")}
+	if code := run(args, &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("exit code = %d, want 0", code)
+	}
+	data, err := os.ReadFile(script)
+	if err != nil || !strings.Contains(string(data), "go test -run '^TestA$' example.com/foo") {
+		t.Errorf("script = %q, %v, want a command rerunning TestA", data, err)
+	}
+}
+
+func TestPackageFlag(t *testing.T) {
+	var p packageFlag
+	for _, v := range []string{"example.com/all", "foo.test.json=example.com/foo"} {
+		if err := p.Set(v); err != nil {
+			t.Fatalf("Set(%q) returned error: %v", v, err)
+		}
+	}
+
+	if p.pkg != "example.com/all" {
+		t.Errorf("pkg = %q, want %q", p.pkg, "example.com/all")
+	}
+	if got := p.byInput["foo.test.json"]; got != "example.com/foo" {
+		t.Errorf("byInput[foo.test.json] = %q, want %q", got, "example.com/foo")
+	}
+}
+
+func TestGoldenFlag(t *testing.T) {
+	var g goldenFlag
+	if err := g.Set(`golden (?P<file>\S+) differs`); err != nil {
+		t.Fatalf("Set() returned error: %v", err)
+	}
+	if len(g) != 1 || g.String() != `golden (?P<file>\S+) differs` {
+		t.Errorf("flag = %v", g.String())
+	}
+
+	for _, v := range []string{`golden (\S+) differs`, `golden (`} {
+		if err := g.Set(v); err == nil {
+			t.Errorf("Set(%q) returned no error", v)
+		}
+	}
+}
+
+func TestExpandInputs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.json", "b.json", "c.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	a := filepath.Join(dir, "a.json")
+	b := filepath.Join(dir, "b.json")
+	got, err := expandInputs([]string{a, filepath.Join(dir, "*.json"), "literal.json"})
+	if err != nil {
+		t.Fatalf("expandInputs returned error: %v", err)
+	}
+
+	want := []string{a, b, "literal.json"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("expandInputs() = %v, want %v", got, want)
+	}
+}
+
 func TestPrintVersion(t *testing.T) {
 	buf := &bytes.Buffer{}
 	printVersion(buf)
@@ -163,6 +415,9 @@ func TestPrintUsage(t *testing.T) {
 	if !strings.Contains(output, "--pretty") {
 		t.Errorf("printUsage() = %q, want to contain --pretty flag", output)
 	}
+	if !strings.Contains(output, "--output") {
+		t.Errorf("printUsage() = %q, want to contain --output flag", output)
+	}
 }
 
 func setupValidTestFiles() (string, string, func()) {
@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
+	"github.com/ivuorinen/go-test-sarif-action/internal/source"
+)
+
+// artifactDir returns the artifact directory of a test: the directory
+// reported by an artifacts event if any, otherwise pattern with its
+// {package} and {test} placeholders expanded.
+func artifactDir(reported, pattern string, k testKey) string {
+	if reported != "" || pattern == "" {
+		return reported
+	}
+	return strings.NewReplacer(
+		"{package}", k.pkg,
+		"{test}", k.test,
+	).Replace(pattern)
+}
+
+// collectArtifacts enumerates the files below dir and returns them as
+// result attachments together with their run artifact entries. A missing
+// or unreadable directory yields no attachments.
+func collectArtifacts(dir, test string, resolver *source.Resolver) ([]sarif.Attachment, []sarif.Artifact) {
+	var (
+		attachments []sarif.Attachment
+		artifacts   []sarif.Artifact
+	)
+
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(dir, path)
+		abs, _ := filepath.Abs(path)
+		uri := resolver.URI(abs)
+		attachments = append(attachments, sarif.Attachment{
+			URI:         uri,
+			Description: test + " artifact " + filepath.ToSlash(rel),
+		})
+
+		artifact := sarif.Artifact{URI: uri}
+		if info, err := d.Info(); err == nil {
+			artifact.Length = info.Size()
+		}
+		artifact.SHA256, _ = hashFile(path)
+		artifacts = append(artifacts, artifact)
+		return nil
+	})
+
+	return attachments, artifacts
+}
+
+// hashFile returns the hex-encoded SHA-256 digest of the file at path.
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// addArtifacts appends artifacts to the report, skipping already listed URIs.
+func addArtifacts(report *sarif.Report, artifacts []sarif.Artifact) {
+	for _, a := range artifacts {
+		listed := false
+		for _, existing := range report.Artifacts {
+			if existing.URI == a.URI {
+				listed = true
+				break
+			}
+		}
+		if !listed {
+			report.Artifacts = append(report.Artifacts, a)
+		}
+	}
+}
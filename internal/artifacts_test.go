@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
+	"github.com/ivuorinen/go-test-sarif-action/internal/source"
+)
+
+func TestArtifactDir(t *testing.T) {
+	k := testKey{pkg: "example.com/foo", test: "TestBar/case"}
+
+	if got := artifactDir("/reported", "artifacts/{package}/{test}", k); got != "/reported" {
+		t.Errorf("artifactDir() = %q, want reported directory", got)
+	}
+	if got := artifactDir("", "artifacts/{package}/{test}", k); got != "artifacts/example.com/foo/TestBar/case" {
+		t.Errorf("artifactDir() = %q, want expanded pattern", got)
+	}
+	if got := artifactDir("", "", k); got != "" {
+		t.Errorf("artifactDir() = %q, want empty", got)
+	}
+}
+
+func TestCollectArtifacts(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "shot.png"), []byte("png"), 0o600); err != nil {
+		t.Fatalf("failed to write artifact: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sub", "dump.txt"), []byte("dump"), 0o600); err != nil {
+		t.Fatalf("failed to write artifact: %v", err)
+	}
+
+	attachments, artifacts := collectArtifacts(dir, "TestBar", source.NewResolver(""))
+
+	if len(attachments) != 2 || len(artifacts) != 2 {
+		t.Fatalf("got %d attachments and %d artifacts, want 2 each", len(attachments), len(artifacts))
+	}
+	if !strings.HasSuffix(attachments[0].URI, "/shot.png") {
+		t.Errorf("attachments[0].URI = %q, want shot.png", attachments[0].URI)
+	}
+	if attachments[1].Description != "TestBar artifact sub/dump.txt" {
+		t.Errorf("attachments[1].Description = %q", attachments[1].Description)
+	}
+	// SHA-256 of "png"
+	if want := "8f8cbb7dcf46e0bc7d53265749a6c17d116093a6ba95e442764060c76fd4a86c"; artifacts[0].SHA256 != want {
+		t.Errorf("artifacts[0].SHA256 = %q, want %q", artifacts[0].SHA256, want)
+	}
+	if artifacts[1].Length != 4 {
+		t.Errorf("artifacts[1].Length = %d, want 4", artifacts[1].Length)
+	}
+}
+
+func TestCollectArtifacts_MissingDir(t *testing.T) {
+	attachments, artifacts := collectArtifacts(filepath.Join(t.TempDir(), "missing"), "TestBar", source.NewResolver(""))
+
+	if len(attachments) != 0 || len(artifacts) != 0 {
+		t.Errorf("got %d attachments and %d artifacts, want none", len(attachments), len(artifacts))
+	}
+}
+
+func TestAddArtifacts_Dedup(t *testing.T) {
+	report := &sarif.Report{}
+	a := sarif.Artifact{URI: "a.png"}
+
+	addArtifacts(report, []sarif.Artifact{a})
+	addArtifacts(report, []sarif.Artifact{a, {URI: "b.png"}})
+
+	if len(report.Artifacts) != 2 {
+		t.Errorf("len(Artifacts) = %d, want 2", len(report.Artifacts))
+	}
+}
@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/recognize"
+	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
+	"github.com/ivuorinen/go-test-sarif-action/internal/source"
+)
+
+// describeAssertionFailure extracts the failed assertion from the output of
+// a failed test. Assertions of a recognized library replace the message
+// with their title and values, and every recognized failure is located at
+// the assertion.
+func describeAssertionFailure(result *sarif.Result, c *testCase, rs []recognize.Recognizer, resolver *source.Resolver) {
+	output := c.outputText()
+	f, ok := recognize.Recognize(rs, output)
+	if !ok {
+		return
+	}
+	f.Golden = strings.ReplaceAll(f.Golden, "{test}", c.test)
+
+	if f.Line > 0 {
+		if file, ok := assertionFile(f.File, c.pkg, resolver); ok {
+			result.PhysicalLocation = &sarif.PhysicalLocation{
+				URI:       resolver.URI(file),
+				StartLine: f.Line,
+			}
+		}
+	}
+	if !f.Structured() {
+		return
+	}
+
+	title := f.Title
+	if title == "" {
+		title = c.message()
+	}
+	result.Message = title + "\n\n" + output
+
+	var md strings.Builder
+	fmt.Fprintf(&md, "%s\n", title)
+	for _, section := range []struct{ name, text, lang string }{
+		{"Command", f.Command, "sh"},
+		{"Expected", f.Expected, ""},
+		{"Actual", f.Actual, ""},
+		{"Diff", f.Diff, "diff"},
+		{"Stdout", f.Stdout, ""},
+		{"Stderr", f.Stderr, ""},
+	} {
+		if section.text != "" {
+			fmt.Fprintf(&md, "\n**%s**\n\n```%s\n%s\n```\n", section.name, section.lang, section.text)
+		}
+	}
+	result.Markdown = md.String()
+
+	if result.Properties == nil {
+		result.Properties = map[string]any{}
+	}
+	assertion := map[string]any{"library": f.Recognizer}
+	for key, value := range map[string]string{
+		"expected": f.Expected,
+		"actual":   f.Actual,
+		"diff":     f.Diff,
+		"command":  f.Command,
+		"stdout":   f.Stdout,
+		"stderr":   f.Stderr,
+		"golden":   f.Golden,
+	} {
+		if value != "" {
+			assertion[key] = value
+		}
+	}
+	result.Properties["assertion"] = assertion
+
+	if f.Golden != "" {
+		describeGoldenFailure(result, f, c.pkg, resolver)
+	}
+}
+
+// assertionFile resolves the file of an assertion. Absolute paths are used
+// as printed, while bare file names, as printed by the testing package,
+// and script paths relative to the package, as printed by testscript, are
+// looked up in the directory of pkg.
+func assertionFile(file, pkg string, resolver *source.Resolver) (string, bool) {
+	if filepath.IsAbs(file) {
+		return file, true
+	}
+	dir, ok := resolver.PackageDir(pkg)
+	if !ok {
+		return "", false
+	}
+	path := filepath.Join(dir, file)
+	if _, err := os.Stat(path); err != nil {
+		return "", false
+	}
+	return path, true
+}
@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/recognize"
+	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
+	"github.com/ivuorinen/go-test-sarif-action/internal/testjson"
+)
+
+func TestDescribeAssertionFailure(t *testing.T) {
+	resolver := writeTestModule(t, map[string]string{"foo/foo_test.go": "package foo\n"})
+	events := testOutput("TestFoo",
+		"=== RUN   TestFoo\n",
+		"    foo_test.go:12: \n",
+		"        \tError Trace:\tfoo_test.go:12\n",
+		"        \tError:      \tNot equal: \n",
+		"        \t            \texpected: 1\n",
+		"        \t            \tactual  : 2\n",
+		"        \tTest:       \tTestFoo\n",
+		"--- FAIL: TestFoo (0.00s)\n",
+	)
+	events = append(events, testjson.TestEvent{Action: "fail", Package: testPkg, Test: "TestFoo"})
+	c := collectTests(events)[0]
+
+	result := sarif.Result{Message: c.message()}
+	describeAssertionFailure(&result, c, recognize.Default(), resolver)
+
+	if !strings.HasPrefix(result.Message, "Not equal\n\n") {
+		t.Errorf("Message = %q, want the assertion title first", result.Message)
+	}
+	if !strings.Contains(result.Markdown, "**Expected**\n\n```\n1\n```") {
+		t.Errorf("Markdown = %q, want the expected value", result.Markdown)
+	}
+	assertion, _ := result.Properties["assertion"].(map[string]any)
+	if assertion["library"] != "testify" || assertion["expected"] != "1" || assertion["actual"] != "2" {
+		t.Errorf("assertion = %v, want testify values", assertion)
+	}
+	loc := result.PhysicalLocation
+	if loc == nil || loc.URI != "foo/foo_test.go" || loc.StartLine != 12 {
+		t.Errorf("PhysicalLocation = %+v, want foo/foo_test.go:12", loc)
+	}
+}
+
+func TestDescribeAssertionFailure_Plain(t *testing.T) {
+	events := testOutput("TestFoo", "    foo_test.go:7: boom\n")
+	c := collectTests(events)[0]
+
+	result := sarif.Result{Message: c.message()}
+	describeAssertionFailure(&result, c, recognize.Default(), writeTestModule(t, map[string]string{}))
+
+	// The file does not exist, so the result is left unlocated.
+	if result.Message != "foo_test.go:7: boom" || result.Markdown != "" || result.PhysicalLocation != nil {
+		t.Errorf("result = %+v, want unchanged", result)
+	}
+}
+
+func TestDescribeAssertionFailure_NoRecognizers(t *testing.T) {
+	events := testOutput("TestFoo", "    foo_test.go:7: assertion failed: 1 (got int) != 2 (want int)\n")
+	c := collectTests(events)[0]
+
+	result := sarif.Result{Message: c.message()}
+	describeAssertionFailure(&result, c, []recognize.Recognizer{}, writeTestModule(t, map[string]string{}))
+
+	if result.Properties != nil {
+		t.Errorf("Properties = %v, want none", result.Properties)
+	}
+}
+
+func TestDescribeAssertionFailure_Testscript(t *testing.T) {
+	resolver := writeTestModule(t, map[string]string{
+		"foo/foo_test.go":                 "package foo\n",
+		"foo/testdata/script/hello.txtar": "exec hello\nstdout 'bye'\n",
+	})
+	events := testOutput("TestScript/hello",
+		"=== RUN   TestScript/hello\n",
+		"    testscript.go:584: # default (0.000s)\n",
+		"        > exec hello\n",
+		"        [stdout]\n",
+		"        hi\n",
+		"        > stdout 'bye'\n",
+		"        FAIL: testdata/script/hello.txtar:2: no match for `bye` found in stdout\n",
+		"--- FAIL: TestScript/hello (0.00s)\n",
+	)
+	events = append(events, testjson.TestEvent{Action: "fail", Package: testPkg, Test: "TestScript/hello"})
+	c := collectTests(events)[0]
+
+	result := sarif.Result{Message: c.message()}
+	describeAssertionFailure(&result, c, recognize.Default(), resolver)
+
+	loc := result.PhysicalLocation
+	if loc == nil || loc.URI != "foo/testdata/script/hello.txtar" || loc.StartLine != 2 {
+		t.Errorf("PhysicalLocation = %+v, want the script line", loc)
+	}
+	if !strings.Contains(result.Markdown, "**Command**\n\n```sh\nstdout 'bye'\n```") ||
+		!strings.Contains(result.Markdown, "**Stdout**\n\n```\nhi\n```") {
+		t.Errorf("Markdown = %q, want the command and its stdout", result.Markdown)
+	}
+	assertion, _ := result.Properties["assertion"].(map[string]any)
+	if assertion["library"] != "testscript" || assertion["stdout"] != "hi" {
+		t.Errorf("assertion = %v, want testscript values", assertion)
+	}
+}
@@ -0,0 +1,119 @@
+package internal
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
+)
+
+// AttrMapping selects test attributes, set with testing.T.Attr, whose
+// values control fields of the test's SARIF result. Empty keys are unused.
+type AttrMapping struct {
+	// LevelKey names the attribute that overrides the result level
+	// (error, warning, note or none).
+	LevelKey string
+	// TagsKey names the attribute holding comma-separated rule tags.
+	TagsKey string
+	// OwnersKey names the attribute holding comma-separated test owners.
+	OwnersKey string
+	// HelpURIKey names the attribute holding a help or issue link.
+	HelpURIKey string
+}
+
+// attribute is a key/value pair reported by an attr event.
+type attribute struct {
+	Key   string
+	Value string
+}
+
+// validLevels are the result levels defined by SARIF.
+var validLevels = map[string]bool{
+	"error":   true,
+	"warning": true,
+	"note":    true,
+	"none":    true,
+}
+
+// applyAttributes records attrs in the properties of result, reported
+// under rule, applies the fields selected by m and returns the rule of
+// the result. Repeated keys keep their last value, except for the tags
+// and owners keys whose values accumulate.
+//
+// SARIF consumers read tags and help links from rules only, so a test
+// with either gets a rule of its own, derived from rule.
+func applyAttributes(result *sarif.Result, rule sarif.Rule, attrs []attribute, m AttrMapping) sarif.Rule {
+	if len(attrs) == 0 {
+		return rule
+	}
+
+	values := make(map[string]string, len(attrs))
+	var tags, owners []string
+	for _, a := range attrs {
+		values[a.Key] = a.Value
+		switch a.Key {
+		case m.TagsKey:
+			tags = append(tags, splitList(a.Value)...)
+		case m.OwnersKey:
+			owners = append(owners, splitList(a.Value)...)
+		}
+	}
+
+	if result.Properties == nil {
+		result.Properties = map[string]any{}
+	}
+	result.Properties["attributes"] = values
+
+	if m.LevelKey != "" {
+		if level := strings.ToLower(values[m.LevelKey]); validLevels[level] {
+			result.Level = level
+		}
+	}
+	if m.OwnersKey != "" && len(owners) > 0 {
+		result.Properties["owners"] = owners
+	}
+
+	var helpURI string
+	if m.HelpURIKey != "" {
+		helpURI = values[m.HelpURIKey]
+	}
+	if m.TagsKey == "" {
+		tags = nil
+	}
+	if helpURI == "" && len(tags) == 0 {
+		return rule
+	}
+	slices.Sort(tags)
+	return testRule(result, rule, slices.Compact(tags), helpURI)
+}
+
+// testRule returns a copy of rule specific to the test of result, with
+// the given tags and help link, and reports result under it.
+func testRule(result *sarif.Result, rule sarif.Rule, tags []string, helpURI string) sarif.Rule {
+	name := ""
+	if loc := result.Location; loc != nil {
+		name = loc.Function
+		if loc.Module != "" {
+			name = loc.Module + "." + name
+		}
+	}
+	rule.ID += "/" + name
+	rule.Description += " in " + name
+	rule.Tags = tags
+	if helpURI != "" {
+		rule.HelpURI = helpURI
+	}
+	result.RuleID = rule.ID
+	return rule
+}
+
+// splitList splits a comma-separated attribute value, dropping empty items.
+func splitList(value string) []string {
+	var items []string
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,109 @@
+package internal
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
+	"github.com/ivuorinen/go-test-sarif-action/internal/testjson"
+)
+
+func TestApplyAttributes(t *testing.T) {
+	attrs := []attribute{
+		{Key: "severity", Value: "Warning"},
+		{Key: "tags", Value: "slow, db"},
+		{Key: "tags", Value: "flaky"},
+		{Key: "owner", Value: "team-a"},
+		{Key: "issue", Value: "https://example.com/issues/1"},
+		{Key: "other", Value: "x"},
+	}
+	m := AttrMapping{
+		LevelKey:   "severity",
+		TagsKey:    "tags",
+		OwnersKey:  "owner",
+		HelpURIKey: "issue",
+	}
+
+	result := sarif.Result{
+		RuleID:   failureRule.ID,
+		Level:    "error",
+		Location: &sarif.LogicalLocation{Module: "example.com/app", Function: "TestA"},
+	}
+	rule := applyAttributes(&result, failureRule, attrs, m)
+
+	if result.Level != "warning" {
+		t.Errorf("Level = %q, want %q", result.Level, "warning")
+	}
+	wantRule := sarif.Rule{
+		ID:          "go-test-failure/example.com/app.TestA",
+		Description: "go test failure in example.com/app.TestA",
+		HelpURI:     "https://example.com/issues/1",
+		Tags:        []string{"db", "flaky", "slow"},
+	}
+	if !reflect.DeepEqual(rule, wantRule) {
+		t.Errorf("rule = %+v, want %+v", rule, wantRule)
+	}
+	if result.RuleID != wantRule.ID {
+		t.Errorf("RuleID = %q, want %q", result.RuleID, wantRule.ID)
+	}
+	if got, want := result.Properties["owners"], []string{"team-a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("owners = %v, want %v", got, want)
+	}
+	values, _ := result.Properties["attributes"].(map[string]string)
+	if values["other"] != "x" || values["tags"] != "flaky" {
+		t.Errorf("attributes = %v, want all attributes with last values", values)
+	}
+}
+
+func TestApplyAttributes_InvalidLevel(t *testing.T) {
+	result := sarif.Result{Level: "error"}
+	rule := applyAttributes(&result, failureRule, []attribute{{Key: "severity", Value: "critical"}}, AttrMapping{LevelKey: "severity"})
+
+	if result.Level != "error" {
+		t.Errorf("Level = %q, want unchanged %q", result.Level, "error")
+	}
+	if rule.ID != failureRule.ID {
+		t.Errorf("rule = %q, want %q without tags or help link", rule.ID, failureRule.ID)
+	}
+}
+
+func TestApplyAttributes_None(t *testing.T) {
+	result := sarif.Result{Level: "error"}
+	applyAttributes(&result, failureRule, nil, AttrMapping{LevelKey: "severity"})
+
+	if result.Properties != nil {
+		t.Errorf("Properties = %v, want nil", result.Properties)
+	}
+}
+
+func TestBuildReport_AttributeRules(t *testing.T) {
+	events := []testjson.TestEvent{
+		{Action: "run", Package: testPkg, Test: "TestA"},
+		{Action: "attr", Package: testPkg, Test: "TestA", Key: "issue", Value: "https://example.com/issues/1"},
+		{Action: "attr", Package: testPkg, Test: "TestA", Key: "tags", Value: "db"},
+		{Action: "fail", Package: testPkg, Test: "TestA"},
+	}
+	events = append(events, runEvents("TestB", "fail")...)
+	opts := ConvertOptions{Attributes: AttrMapping{TagsKey: "tags", HelpURIKey: "issue"}}
+
+	report := buildReport([]input{{Path: "attrs.json", Events: events}}, opts)
+
+	ruleA := "go-test-failure/" + testPkg + ".TestA"
+	for _, r := range report.Results {
+		want := failureRule.ID
+		if r.Location.Function == "TestA" {
+			want = ruleA
+		}
+		if r.RuleID != want {
+			t.Errorf("%s: RuleID = %q, want %q", r.Location.Function, r.RuleID, want)
+		}
+	}
+	i := slices.IndexFunc(report.Rules, func(r sarif.Rule) bool { return r.ID == ruleA })
+	if i < 0 {
+		t.Fatalf("rules = %+v, want %s", report.Rules, ruleA)
+	}
+	if r := report.Rules[i]; r.HelpURI != "https://example.com/issues/1" || !slices.Equal(r.Tags, []string{"db"}) {
+		t.Errorf("rule = %+v, want the help link and tags of TestA", r)
+	}
+}
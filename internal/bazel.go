@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/bazel"
+	"github.com/ivuorinen/go-test-sarif-action/internal/source"
+)
+
+// readBazelTestlogs reads the test targets below a bazel-testlogs
+// directory, one input per target, shard or run. Each input is named
+// after its test.xml file.
+func readBazelTestlogs(dir string) ([]input, error) {
+	targets, err := bazel.FindTargets(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no test.xml files found below %s", dir)
+	}
+
+	inputs := make([]input, 0, len(targets))
+	for _, t := range targets {
+		events, err := bazel.Events(t)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", t.Label, err)
+		}
+		inputs = append(inputs, input{
+			Path:   t.XMLPath(),
+			Format: formatBazelTestlogs,
+			Events: events,
+			Target: &t,
+		})
+	}
+	return inputs, nil
+}
+
+// importPathRe matches the importpath attribute of a rules_go rule.
+var importPathRe = regexp.MustCompile(`(?m)^\s*importpath\s*=\s*"([^"]+)"`)
+
+// bazelImportPath returns the Go import path of the package of t: the
+// importpath declared in its BUILD file, the path derived from the module
+// at the source root, or else the Bazel package itself.
+func bazelImportPath(t bazel.Target, resolver *source.Resolver) string {
+	if strings.HasPrefix(t.Label, "@") {
+		return t.Package
+	}
+
+	dir := filepath.Join(resolver.Root(), filepath.FromSlash(t.Package))
+	for _, name := range []string{"BUILD.bazel", "BUILD"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		if m := importPathRe.FindSubmatch(data); m != nil {
+			return string(m[1])
+		}
+	}
+
+	if pkg, ok := resolver.ImportPath(dir); ok {
+		return pkg
+	}
+	return t.Package
+}
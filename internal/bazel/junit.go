@@ -0,0 +1,96 @@
+package bazel
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Suite is a JUnit test suite as written to test.xml.
+type Suite struct {
+	// Name is the suite name.
+	Name string `xml:"name,attr"`
+	// Errors is the number of test cases that errored.
+	Errors int `xml:"errors,attr"`
+	// Failures is the number of test cases that failed.
+	Failures int `xml:"failures,attr"`
+	// Time is the duration of the suite in seconds.
+	Time string `xml:"time,attr"`
+	// Cases are the test cases of the suite.
+	Cases []Case `xml:"testcase"`
+	// Suites are nested suites.
+	Suites []Suite `xml:"testsuite"`
+	// SystemOut is output not attributed to a test case.
+	SystemOut string `xml:"system-out"`
+}
+
+// Case is a JUnit test case.
+type Case struct {
+	// Name is the test name.
+	Name string `xml:"name,attr"`
+	// ClassName is the class or package of the test.
+	ClassName string `xml:"classname,attr"`
+	// Time is the duration of the test in seconds.
+	Time string `xml:"time,attr"`
+	// Failure is set when the test failed.
+	Failure *Outcome `xml:"failure"`
+	// Error is set when the test errored.
+	Error *Outcome `xml:"error"`
+	// Skipped is set when the test was skipped.
+	Skipped *Outcome `xml:"skipped"`
+	// SystemOut is the output of the test.
+	SystemOut string `xml:"system-out"`
+}
+
+// Outcome describes a failure, error or skip of a test case.
+type Outcome struct {
+	// Message summarizes the outcome.
+	Message string `xml:"message,attr"`
+	// Type classifies the outcome.
+	Type string `xml:"type,attr"`
+	// Text is the detail, usually the test output.
+	Text string `xml:",chardata"`
+}
+
+// Seconds returns the duration of the test case in seconds, or 0 if
+// unknown.
+func (c Case) Seconds() float64 {
+	s, _ := strconv.ParseFloat(c.Time, 64)
+	return s
+}
+
+// ParseXML reads the test suites of a JUnit XML file, whose root element
+// is either <testsuites> or a single <testsuite>. Nested suites are
+// flattened.
+func ParseXML(r io.Reader) ([]Suite, error) {
+	var root struct {
+		XMLName xml.Name
+		Suite
+	}
+	if err := xml.NewDecoder(r).Decode(&root); err != nil {
+		return nil, fmt.Errorf("invalid XML: %w", err)
+	}
+
+	var suites []Suite
+	var flatten func(s Suite)
+	flatten = func(s Suite) {
+		if len(s.Cases) > 0 || s.Errors > 0 || s.Failures > 0 || s.SystemOut != "" {
+			suites = append(suites, s)
+		}
+		for _, nested := range s.Suites {
+			flatten(nested)
+		}
+	}
+	switch root.XMLName.Local {
+	case "testsuites":
+		for _, s := range root.Suites {
+			flatten(s)
+		}
+	case "testsuite":
+		flatten(root.Suite)
+	default:
+		return nil, fmt.Errorf("unexpected root element <%s>", root.XMLName.Local)
+	}
+	return suites, nil
+}
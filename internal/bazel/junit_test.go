@@ -0,0 +1,79 @@
+package bazel
+
+import (
+	"strings"
+	"testing"
+)
+
+// rulesGoXML is a test.xml as written by a rules_go test binary.
+const rulesGoXML = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+	<testsuite errors="1" failures="1" skipped="1" tests="4" time="0.412" name="example.com/app/foo">
+		<testcase classname="foo" name="TestAdd" time="0.010"></testcase>
+		<testcase classname="foo" name="TestSub" time="0.020">
+			<failure message="Failed" type="">=== RUN   TestSub
+    foo_test.go:12: got 1, want 2
+--- FAIL: TestSub (0.02s)
+</failure>
+		</testcase>
+		<testcase classname="foo" name="TestSkip" time="0.000">
+			<skipped message="Skipped" type="">    foo_test.go:20: not on CI</skipped>
+		</testcase>
+		<testcase classname="foo" name="TestHang" time="0.000">
+			<error message="No pass/skip/fail event found for test" type="">=== RUN   TestHang
+</error>
+		</testcase>
+		<testsuite name="nested" failures="0" errors="0">
+			<testcase classname="foo" name="TestNested" time="0.001"></testcase>
+		</testsuite>
+	</testsuite>
+</testsuites>
+`
+
+func TestParseXML(t *testing.T) {
+	suites, err := ParseXML(strings.NewReader(rulesGoXML))
+	if err != nil {
+		t.Fatalf("ParseXML() error = %v", err)
+	}
+	if len(suites) != 2 {
+		t.Fatalf("got %d suites, want the outer and the nested suite", len(suites))
+	}
+
+	s := suites[0]
+	if s.Name != "example.com/app/foo" || s.Errors != 1 || s.Failures != 1 || len(s.Cases) != 4 {
+		t.Errorf("suite = %+v", s)
+	}
+	sub := s.Cases[1]
+	if sub.Failure == nil || !strings.Contains(sub.Failure.Text, "got 1, want 2") {
+		t.Errorf("TestSub failure = %+v", sub.Failure)
+	}
+	if got := sub.Seconds(); got != 0.02 {
+		t.Errorf("TestSub Seconds() = %v, want 0.02", got)
+	}
+	if s.Cases[2].Skipped == nil || s.Cases[3].Error == nil {
+		t.Errorf("skipped and errored cases = %+v, %+v", s.Cases[2], s.Cases[3])
+	}
+	if suites[1].Cases[0].Name != "TestNested" {
+		t.Errorf("nested suite = %+v", suites[1])
+	}
+}
+
+func TestParseXML_SingleSuite(t *testing.T) {
+	xml := `<testsuite name="s"><testcase name="TestA" time="1.5"/></testsuite>`
+	suites, err := ParseXML(strings.NewReader(xml))
+	if err != nil {
+		t.Fatalf("ParseXML() error = %v", err)
+	}
+	if len(suites) != 1 || suites[0].Cases[0].Seconds() != 1.5 {
+		t.Errorf("suites = %+v", suites)
+	}
+}
+
+func TestParseXML_Invalid(t *testing.T) {
+	if _, err := ParseXML(strings.NewReader(`<report/>`)); err == nil {
+		t.Error("expected an error for an unexpected root element")
+	}
+	if _, err := ParseXML(strings.NewReader(`<testsuites>`)); err == nil {
+		t.Error("expected an error for truncated XML")
+	}
+}
@@ -0,0 +1,218 @@
+// Package bazel reads the test logs that Bazel writes for Go tests built
+// with rules_go, below the bazel-testlogs directory.
+package bazel
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/testjson"
+)
+
+// Target is the test output of a Bazel test target, or of one shard or
+// run of it.
+type Target struct {
+	// Label is the target label, as in "//pkg/foo:foo_test".
+	Label string
+	// Package is the Bazel package of the target, as in "pkg/foo".
+	Package string
+	// Name is the target name, as in "foo_test".
+	Name string
+	// Dir is the directory holding test.xml and test.log.
+	Dir string
+}
+
+// XMLPath returns the path of the target's JUnit XML file.
+func (t Target) XMLPath() string {
+	return filepath.Join(t.Dir, "test.xml")
+}
+
+// LogPath returns the path of the target's test log.
+func (t Target) LogPath() string {
+	return filepath.Join(t.Dir, "test.log")
+}
+
+// splitDirRe matches the directories Bazel creates for test shards and
+// for repeated runs.
+var splitDirRe = regexp.MustCompile(`^(shard|run)_\d+_of_\d+$`)
+
+// FindTargets walks a bazel-testlogs directory, which may be a symbolic
+// link, and returns the targets with a test.xml file in lexical order.
+func FindTargets(root string) ([]Target, error) {
+	resolved, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []Target
+	err = filepath.WalkDir(resolved, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || d.Name() != "test.xml" {
+			return nil
+		}
+		dir := filepath.Dir(path)
+		rel, err := filepath.Rel(resolved, dir)
+		if err != nil {
+			return err
+		}
+		if t, ok := newTarget(filepath.ToSlash(rel)); ok {
+			t.Dir = dir
+			targets = append(targets, t)
+		}
+		return nil
+	})
+	return targets, err
+}
+
+// newTarget derives the target of the test.xml file in the directory rel
+// below bazel-testlogs.
+func newTarget(rel string) (Target, bool) {
+	parts := strings.Split(rel, "/")
+	for len(parts) > 0 && splitDirRe.MatchString(parts[len(parts)-1]) {
+		parts = parts[:len(parts)-1]
+	}
+	if len(parts) == 0 || parts[0] == "." {
+		return Target{}, false
+	}
+
+	t := Target{Name: parts[len(parts)-1], Package: strings.Join(parts[:len(parts)-1], "/")}
+	repo := ""
+	if len(parts) > 2 && parts[0] == "external" {
+		// Targets of external repositories are logged below
+		// external/<repo>.
+		repo = "@" + parts[1]
+		t.Package = strings.Join(parts[2:len(parts)-1], "/")
+	}
+	t.Label = repo + "//" + t.Package + ":" + t.Name
+	return t, true
+}
+
+// goTestPrefixes start the names of the functions go test runs.
+var goTestPrefixes = []string{"Test", "Benchmark", "Example", "Fuzz"}
+
+// isGoTest reports whether name names a Go test, as opposed to the
+// placeholder test case Bazel writes for test binaries that produce no
+// XML of their own.
+func isGoTest(name string) bool {
+	top, _, _ := strings.Cut(name, "/")
+	return slices.ContainsFunc(goTestPrefixes, func(p string) bool {
+		return strings.HasPrefix(top, p)
+	})
+}
+
+// noTerminalEvent is the error rules_go reports for tests that were still
+// running when the test binary exited.
+const noTerminalEvent = "No pass/skip/fail event found for test"
+
+// Events converts the test results of t to go test JSON events, without
+// a Package. Tests that were running when the binary exited are left
+// unfinished, as in go test output. When the target failed without a
+// failed test, the test log becomes the package output.
+func Events(t Target) ([]testjson.TestEvent, error) {
+	f, err := os.Open(t.XMLPath())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	suites, err := ParseXML(f)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []testjson.TestEvent
+	var elapsed float64
+	failed, testFailed := false, false
+	for _, s := range suites {
+		failed = failed || s.Errors > 0 || s.Failures > 0
+		for _, c := range s.Cases {
+			elapsed += c.Seconds()
+			outcome := c.Failure
+			if outcome == nil {
+				outcome = c.Error
+			}
+			if !isGoTest(c.Name) {
+				failed = failed || outcome != nil
+				continue
+			}
+
+			events = append(events, testjson.TestEvent{Action: "run", Test: c.Name})
+			text := c.SystemOut
+			switch {
+			case outcome != nil:
+				text = outcome.Text + c.SystemOut
+			case c.Skipped != nil && c.Skipped.Text != "":
+				text = c.Skipped.Text
+			}
+			for _, line := range lines(text) {
+				events = append(events, testjson.TestEvent{Action: "output", Test: c.Name, Output: line})
+			}
+
+			action := "pass"
+			switch {
+			case c.Error != nil && strings.Contains(c.Error.Message, noTerminalEvent):
+				failed = true
+				continue
+			case outcome != nil:
+				action = "fail"
+				failed, testFailed = true, true
+			case c.Skipped != nil:
+				action = "skip"
+			}
+			events = append(events, testjson.TestEvent{Action: action, Test: c.Name, Elapsed: c.Seconds()})
+		}
+	}
+
+	if !failed {
+		return append(events, testjson.TestEvent{Action: "pass", Elapsed: elapsed}), nil
+	}
+	if !testFailed {
+		log, err := readLog(t.LogPath())
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range log {
+			events = append(events, testjson.TestEvent{Action: "output", Output: line})
+		}
+	}
+	return append(events, testjson.TestEvent{Action: "fail", Elapsed: elapsed}), nil
+}
+
+// readLog returns the lines of a test log, or none if it does not exist.
+func readLog(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var out []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		out = append(out, scanner.Text()+"\n")
+	}
+	return out, scanner.Err()
+}
+
+// lines splits text into newline-terminated lines.
+func lines(text string) []string {
+	var out []string
+	for line := range strings.Lines(text) {
+		if !strings.HasSuffix(line, "\n") {
+			line += "\n"
+		}
+		out = append(out, line)
+	}
+	return out
+}
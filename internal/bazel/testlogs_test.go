@@ -0,0 +1,145 @@
+package bazel
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/testjson"
+)
+
+// writeFiles writes files, keyed by slash-separated path, below root.
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+}
+
+// placeholderXML is the test.xml Bazel writes for a test binary that
+// produced none of its own.
+const placeholderXML = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+<testsuite name="//pkg/bar:bar_test" tests="1" failures="0" errors="1">
+<testcase name="//pkg/bar:bar_test" status="run" duration="1" time="1"><error message="exited with error code 1"></error></testcase>
+</testsuite>
+</testsuites>
+`
+
+func TestFindTargets(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"pkg/foo/foo_test/test.xml":                 rulesGoXML,
+		"pkg/bar/bar_test/shard_1_of_2/test.xml":    placeholderXML,
+		"pkg/bar/bar_test/shard_2_of_2/test.xml":    placeholderXML,
+		"external/dep/lib/lib_test/test.xml":        rulesGoXML,
+		"pkg/foo/foo_test/test.outputs/outputs.zip": "",
+	})
+	link := filepath.Join(t.TempDir(), "bazel-testlogs")
+	if err := os.Symlink(root, link); err != nil {
+		t.Skipf("symbolic links not supported: %v", err)
+	}
+
+	targets, err := FindTargets(link)
+	if err != nil {
+		t.Fatalf("FindTargets() error = %v", err)
+	}
+
+	var labels []string
+	for _, target := range targets {
+		labels = append(labels, target.Label)
+	}
+	want := []string{"@dep//lib:lib_test", "//pkg/bar:bar_test", "//pkg/bar:bar_test", "//pkg/foo:foo_test"}
+	if !reflect.DeepEqual(labels, want) {
+		t.Errorf("labels = %q, want %q", labels, want)
+	}
+	if targets[0].Package != "lib" || targets[3].Package != "pkg/foo" || targets[3].Name != "foo_test" {
+		t.Errorf("targets = %+v", targets)
+	}
+	if !strings.HasSuffix(targets[1].XMLPath(), filepath.Join("shard_1_of_2", "test.xml")) {
+		t.Errorf("XMLPath() = %s", targets[1].XMLPath())
+	}
+}
+
+func TestEvents(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"test.xml": rulesGoXML})
+
+	events, err := Events(Target{Label: "//pkg/foo:foo_test", Dir: dir})
+	if err != nil {
+		t.Fatalf("Events() error = %v", err)
+	}
+
+	actions := map[string]string{}
+	var output []string
+	for _, e := range events {
+		if e.Package != "" {
+			t.Errorf("event has package %q", e.Package)
+		}
+		switch e.Action {
+		case "run":
+		case "output":
+			if e.Test == "TestSub" {
+				output = append(output, e.Output)
+			}
+		default:
+			actions[e.Test] = e.Action
+		}
+	}
+
+	want := map[string]string{"TestAdd": "pass", "TestSub": "fail", "TestSkip": "skip", "TestNested": "pass", "": "fail"}
+	if !reflect.DeepEqual(actions, want) {
+		t.Errorf("terminal actions = %v, want %v", actions, want)
+	}
+	if len(output) != 3 || output[1] != "    foo_test.go:12: got 1, want 2\n" {
+		t.Errorf("TestSub output = %q", output)
+	}
+}
+
+func TestEvents_TargetFailure(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"test.xml": placeholderXML,
+		"test.log": "panic: boom\n\ngoroutine 1 [running]:\nmain.init()\n",
+	})
+
+	events, err := Events(Target{Label: "//pkg/bar:bar_test", Dir: dir})
+	if err != nil {
+		t.Fatalf("Events() error = %v", err)
+	}
+
+	want := []testjson.TestEvent{
+		{Action: "output", Output: "panic: boom\n"},
+		{Action: "output", Output: "\n"},
+		{Action: "output", Output: "goroutine 1 [running]:\n"},
+		{Action: "output", Output: "main.init()\n"},
+		{Action: "fail", Elapsed: 1},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %+v, want %+v", events, want)
+	}
+}
+
+func TestEvents_Passed(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"test.xml": `<testsuites><testsuite name="p"><testcase name="TestA" time="0.5"/></testsuite></testsuites>`,
+	})
+
+	events, err := Events(Target{Dir: dir})
+	if err != nil {
+		t.Fatalf("Events() error = %v", err)
+	}
+	last := events[len(events)-1]
+	if last.Action != "pass" || last.Test != "" || last.Elapsed != 0.5 {
+		t.Errorf("package event = %+v", last)
+	}
+}
@@ -0,0 +1,111 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/bazel"
+)
+
+// bazelFailedXML is the test.xml of a rules_go test with one failed test.
+const bazelFailedXML = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+	<testsuite errors="0" failures="1" tests="2" time="0.03" name="example.com/app/foo">
+		<testcase classname="foo" name="TestAdd" time="0.01"></testcase>
+		<testcase classname="foo" name="TestSub" time="0.02">
+			<failure message="Failed" type="">=== RUN   TestSub
+    foo_test.go:12: got 1, want 2
+--- FAIL: TestSub (0.02s)
+</failure>
+		</testcase>
+	</testsuite>
+</testsuites>
+`
+
+func TestReadBazelTestlogs(t *testing.T) {
+	resolver := writeTestModule(t, map[string]string{
+		"foo/foo_test.go": "package foo\n",
+		"gen/BUILD.bazel": "go_test(\n    name = \"gen_test\",\n    importpath = \"example.com/generated\",\n)\n",
+		"gen/gen_test.go": "package gen\n",
+	})
+	testlogs := t.TempDir()
+	for _, rel := range []string{"foo/foo_test", "gen/gen_test"} {
+		dir := filepath.Join(testlogs, filepath.FromSlash(rel))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "test.xml"), []byte(bazelFailedXML), 0o600); err != nil {
+			t.Fatalf("failed to write test.xml: %v", err)
+		}
+	}
+
+	out := filepath.Join(t.TempDir(), "report.sarif")
+	opts := DefaultConvertOptions()
+	opts.SourceRoot = resolver.Root()
+	if err := ConvertFilesToSARIF([]string{testlogs}, out, opts); err != nil {
+		t.Fatalf("ConvertFilesToSARIF() error = %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if !strings.Contains(string(data), "example.com/generated") {
+		t.Errorf("output does not name the BUILD importpath: %s", data)
+	}
+
+	inputs, err := readInputs([]string{testlogs})
+	if err != nil {
+		t.Fatalf("readInputs() error = %v", err)
+	}
+	if len(inputs) != 2 || inputs[0].Format != formatBazelTestlogs || inputs[0].Target == nil {
+		t.Fatalf("inputs = %+v", inputs)
+	}
+	for i := range inputs {
+		fillPackage(&inputs[i], bazelImportPath(*inputs[i].Target, resolver))
+	}
+
+	results := buildReport(inputs, opts).Results
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want a test and a package result per target: %+v", len(results), results)
+	}
+	if loc := results[0].Location; loc.Module != "example.com/app/foo" || loc.Function != "TestSub" {
+		t.Errorf("first result location = %+v", loc)
+	}
+	if results[2].Location.Module != "example.com/generated" {
+		t.Errorf("third result module = %q, want the BUILD importpath", results[1].Location.Module)
+	}
+	if !strings.Contains(results[0].Message, "got 1, want 2") {
+		t.Errorf("message = %q", results[0].Message)
+	}
+}
+
+func TestReadBazelTestlogs_Empty(t *testing.T) {
+	if _, err := readInputs([]string{t.TempDir()}); err == nil {
+		t.Error("expected an error for a directory without test.xml files")
+	}
+}
+
+func TestBazelImportPath(t *testing.T) {
+	resolver := writeTestModule(t, map[string]string{
+		"lib/BUILD":   "go_library(\n\timportpath = \"example.com/lib\",\n)\n",
+		"app/app.go":  "package app\n",
+		"tools/x.txt": "",
+	})
+
+	tests := []struct {
+		target bazel.Target
+		want   string
+	}{
+		{bazel.Target{Label: "//lib:lib_test", Package: "lib"}, "example.com/lib"},
+		{bazel.Target{Label: "//app:app_test", Package: "app"}, "example.com/app/app"},
+		{bazel.Target{Label: "@dep//lib:lib_test", Package: "lib"}, "lib"},
+		{bazel.Target{Label: "//missing:m_test", Package: "missing"}, "example.com/app/missing"},
+	}
+	for _, tt := range tests {
+		if got := bazelImportPath(tt.target, resolver); got != tt.want {
+			t.Errorf("bazelImportPath(%s) = %q, want %q", tt.target.Label, got, tt.want)
+		}
+	}
+}
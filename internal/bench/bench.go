@@ -0,0 +1,226 @@
+// Package bench extracts Go benchmark results from go test output and
+// writes them in the benchmark format read by benchstat or as JSON.
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/testjson"
+)
+
+// Package holds the benchmark results of one package.
+type Package struct {
+	// ImportPath is the import path of the package.
+	ImportPath string `json:"importPath"`
+	// Config holds the configuration lines printed before the results,
+	// such as goos and cpu, in order.
+	Config []Config `json:"config,omitempty"`
+	// Results are the benchmark results, in order. Benchmarks run more
+	// than once, as with -count, have several results.
+	Results []Result `json:"results"`
+}
+
+// Config is a "key: value" configuration line.
+type Config struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Result is one line of benchmark results.
+type Result struct {
+	// Name is the benchmark name, including sub-benchmark names and
+	// without the GOMAXPROCS suffix.
+	Name string `json:"name"`
+	// Procs is the GOMAXPROCS value the benchmark ran with.
+	Procs int `json:"procs"`
+	// Iterations is the number of iterations run.
+	Iterations int `json:"iterations"`
+	// Metrics are the measurements, in order, such as ns/op and any
+	// reported with testing.B.ReportMetric.
+	Metrics []Metric `json:"metrics"`
+}
+
+// Metric is a measurement with its unit.
+type Metric struct {
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit"`
+}
+
+// Metric returns the value of the metric with the given unit.
+func (r Result) Metric(unit string) (float64, bool) {
+	for _, m := range r.Metrics {
+		if m.Unit == unit {
+			return m.Value, true
+		}
+	}
+	return 0, false
+}
+
+// FullName returns the benchmark name as printed, with the GOMAXPROCS
+// suffix that the testing package omits when it is 1.
+func (r Result) FullName() string {
+	if r.Procs > 1 {
+		return fmt.Sprintf("%s-%d", r.Name, r.Procs)
+	}
+	return r.Name
+}
+
+// procsRe matches the GOMAXPROCS suffix of a benchmark name.
+var procsRe = regexp.MustCompile(`-(\d+)$`)
+
+// ParseLine parses a benchmark result line,
+// "BenchmarkX-8  1000  1234 ns/op  56 B/op".
+func ParseLine(line string) (Result, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || len(fields)%2 != 0 || !strings.HasPrefix(fields[0], "Benchmark") {
+		return Result{}, false
+	}
+	iterations, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return Result{}, false
+	}
+
+	r := Result{Name: fields[0], Procs: 1, Iterations: iterations}
+	if m := procsRe.FindStringSubmatch(r.Name); m != nil {
+		r.Procs, _ = strconv.Atoi(m[1])
+		r.Name = strings.TrimSuffix(r.Name, m[0])
+	}
+	for i := 2; i < len(fields); i += 2 {
+		value, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return Result{}, false
+		}
+		r.Metrics = append(r.Metrics, Metric{Value: value, Unit: fields[i+1]})
+	}
+	return r, true
+}
+
+// configKeys are the configuration lines the testing package prints
+// before running benchmarks.
+var configKeys = map[string]bool{"goos": true, "goarch": true, "pkg": true, "cpu": true}
+
+// parseConfig parses a configuration line.
+func parseConfig(line string) (Config, bool) {
+	key, value, ok := strings.Cut(strings.TrimRight(line, "\r\n"), ": ")
+	if !ok || !configKeys[key] {
+		return Config{}, false
+	}
+	return Config{Key: key, Value: value}, true
+}
+
+// FromEvents extracts the benchmark results from go test JSON events,
+// in order of first appearance of each package. Packages without results
+// are omitted.
+func FromEvents(events []testjson.TestEvent) []Package {
+	type stream struct {
+		pkg  string
+		text strings.Builder
+	}
+	var pkgs []*Package
+	byPath := make(map[string]*Package)
+	var streams []*stream
+	byTest := make(map[[2]string]*stream)
+
+	for _, e := range events {
+		if e.Action != "output" || e.OutputType == "frame" {
+			continue
+		}
+		p := byPath[e.Package]
+		if p == nil {
+			p = &Package{ImportPath: e.Package}
+			byPath[e.Package] = p
+			pkgs = append(pkgs, p)
+		}
+		if c, ok := parseConfig(e.Output); ok && e.Test == "" {
+			p.Config = append(p.Config, c)
+			continue
+		}
+		// Result lines may be split across events, so the output of each
+		// test is joined before it is parsed.
+		key := [2]string{e.Package, e.Test}
+		s := byTest[key]
+		if s == nil {
+			s = &stream{pkg: e.Package}
+			byTest[key] = s
+			streams = append(streams, s)
+		}
+		s.text.WriteString(e.Output)
+	}
+
+	for _, s := range streams {
+		p := byPath[s.pkg]
+		for line := range strings.Lines(s.text.String()) {
+			if r, ok := ParseLine(line); ok {
+				p.Results = append(p.Results, r)
+			}
+		}
+	}
+
+	out := make([]Package, 0, len(pkgs))
+	for _, p := range pkgs {
+		if len(p.Results) > 0 {
+			out = append(out, *p)
+		}
+	}
+	return out
+}
+
+// Merge combines the results of packages with the same import path, as
+// read from several inputs, keeping the first configuration of each.
+func Merge(sets ...[]Package) []Package {
+	var out []Package
+	index := make(map[string]int)
+	for _, pkgs := range sets {
+		for _, p := range pkgs {
+			i, ok := index[p.ImportPath]
+			if !ok {
+				index[p.ImportPath] = len(out)
+				p.Results = append([]Result(nil), p.Results...)
+				out = append(out, p)
+				continue
+			}
+			out[i].Results = append(out[i].Results, p.Results...)
+		}
+	}
+	return out
+}
+
+// WriteText writes pkgs in the Go benchmark format read by benchstat.
+func WriteText(w io.Writer, pkgs []Package) error {
+	var b strings.Builder
+	for i, p := range pkgs {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		for _, c := range p.Config {
+			fmt.Fprintf(&b, "%s: %s\n", c.Key, c.Value)
+		}
+		for _, r := range p.Results {
+			fmt.Fprintf(&b, "%s\t%d", r.FullName(), r.Iterations)
+			for _, m := range r.Metrics {
+				fmt.Fprintf(&b, "\t%s %s", strconv.FormatFloat(m.Value, 'f', -1, 64), m.Unit)
+			}
+			b.WriteByte('\n')
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON writes pkgs as an indented JSON document,
+// {"packages": [...]}.
+func WriteJSON(w io.Writer, pkgs []Package) error {
+	if pkgs == nil {
+		pkgs = []Package{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Packages []Package `json:"packages"`
+	}{pkgs})
+}
@@ -0,0 +1,160 @@
+package bench
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/testjson"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want Result
+		ok   bool
+	}{
+		{
+			line: "BenchmarkJoin-8 \t 1000\t 1234 ns/op\t 56 B/op\t 2 allocs/op\n",
+			want: Result{Name: "BenchmarkJoin", Procs: 8, Iterations: 1000, Metrics: []Metric{
+				{1234, "ns/op"}, {56, "B/op"}, {2, "allocs/op"},
+			}},
+			ok: true,
+		},
+		{
+			line: "BenchmarkSub/x         \t     100\t         9.210 ns/op\t         3.500 widgets/op",
+			want: Result{Name: "BenchmarkSub/x", Procs: 1, Iterations: 100, Metrics: []Metric{
+				{9.21, "ns/op"}, {3.5, "widgets/op"},
+			}},
+			ok: true,
+		},
+		{line: "BenchmarkJoin\n"},
+		{line: "BenchmarkJoin \t 100\t fast ns/op\n"},
+		{line: "BenchmarkJoin \t 100\t 1 ns/op\t 2\n"},
+		{line: "ok  \texample.com/foo\t0.1s\n"},
+	}
+	for _, tt := range tests {
+		got, ok := ParseLine(tt.line)
+		if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseLine(%q) = %+v, %v, want %+v, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// benchEvents is the output of go test -json -bench for one package, with
+// a result line split across events as by older toolchains.
+var benchEvents = []testjson.TestEvent{
+	{Action: "start", Package: "example.com/foo"},
+	{Action: "output", Package: "example.com/foo", Output: "goos: linux\n"},
+	{Action: "output", Package: "example.com/foo", Output: "goarch: amd64\n"},
+	{Action: "output", Package: "example.com/foo", Output: "pkg: example.com/foo\n"},
+	{Action: "run", Package: "example.com/foo", Test: "BenchmarkJoin"},
+	{Action: "output", Package: "example.com/foo", Test: "BenchmarkJoin", Output: "=== RUN   BenchmarkJoin\n", OutputType: "frame"},
+	{Action: "output", Package: "example.com/foo", Test: "BenchmarkJoin", Output: "BenchmarkJoin\n"},
+	{Action: "output", Package: "example.com/foo", Test: "BenchmarkJoin", Output: "BenchmarkJoin-4 \t"},
+	{Action: "output", Package: "example.com/foo", Test: "BenchmarkJoin", Output: "     100\t        81.10 ns/op\n"},
+	{Action: "output", Package: "example.com/foo", Test: "BenchmarkJoin", Output: "BenchmarkJoin-4 \t     100\t        79 ns/op\n"},
+	{Action: "output", Package: "example.com/foo", Output: "PASS\n", OutputType: "frame"},
+	{Action: "pass", Package: "example.com/foo"},
+	{Action: "output", Package: "example.com/bar", Output: "ok  \texample.com/bar\t0.1s\n"},
+	{Action: "pass", Package: "example.com/bar"},
+}
+
+func TestFromEvents(t *testing.T) {
+	pkgs := FromEvents(benchEvents)
+	want := []Package{{
+		ImportPath: "example.com/foo",
+		Config:     []Config{{"goos", "linux"}, {"goarch", "amd64"}, {"pkg", "example.com/foo"}},
+		Results: []Result{
+			{Name: "BenchmarkJoin", Procs: 4, Iterations: 100, Metrics: []Metric{{81.1, "ns/op"}}},
+			{Name: "BenchmarkJoin", Procs: 4, Iterations: 100, Metrics: []Metric{{79, "ns/op"}}},
+		},
+	}}
+	if !reflect.DeepEqual(pkgs, want) {
+		t.Errorf("FromEvents() = %+v, want %+v", pkgs, want)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a := FromEvents(benchEvents)
+	b := []Package{
+		{ImportPath: "example.com/foo", Results: []Result{{Name: "BenchmarkSplit", Procs: 1, Iterations: 1}}},
+		{ImportPath: "example.com/bar", Results: []Result{{Name: "BenchmarkBar", Procs: 1, Iterations: 1}}},
+	}
+
+	got := Merge(a, b)
+	if len(got) != 2 || len(got[0].Results) != 3 || len(got[0].Config) != 3 || got[1].ImportPath != "example.com/bar" {
+		t.Errorf("Merge() = %+v", got)
+	}
+	if len(a[0].Results) != 2 {
+		t.Errorf("Merge() modified its input: %+v", a[0].Results)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	pkgs := FromEvents(benchEvents)
+	pkgs = append(pkgs, Package{
+		ImportPath: "example.com/bar",
+		Results: []Result{{Name: "BenchmarkBar", Procs: 1, Iterations: 10, Metrics: []Metric{
+			{1.5, "ns/op"}, {3, "widgets/op"},
+		}}},
+	})
+
+	var b bytes.Buffer
+	if err := WriteText(&b, pkgs); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+	want := "goos: linux\ngoarch: amd64\npkg: example.com/foo\n" +
+		"BenchmarkJoin-4\t100\t81.1 ns/op\n" +
+		"BenchmarkJoin-4\t100\t79 ns/op\n" +
+		"\n" +
+		"BenchmarkBar\t10\t1.5 ns/op\t3 widgets/op\n"
+	if b.String() != want {
+		t.Errorf("WriteText() = %q, want %q", b.String(), want)
+	}
+
+	// The output parses back to the same results.
+	var reparsed []Result
+	for line := range strings.Lines(b.String()) {
+		if r, ok := ParseLine(line); ok {
+			reparsed = append(reparsed, r)
+		}
+	}
+	if len(reparsed) != 3 || !reflect.DeepEqual(reparsed[2], pkgs[1].Results[0]) {
+		t.Errorf("reparsed = %+v", reparsed)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteJSON(&b, FromEvents(benchEvents)); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	var doc struct {
+		Packages []Package `json:"packages"`
+	}
+	if err := json.Unmarshal(b.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if !reflect.DeepEqual(doc.Packages, FromEvents(benchEvents)) {
+		t.Errorf("decoded = %+v", doc.Packages)
+	}
+
+	b.Reset()
+	if err := WriteJSON(&b, nil); err != nil || b.String() != "{\n  \"packages\": []\n}\n" {
+		t.Errorf("WriteJSON(nil) = %q, %v", b.String(), err)
+	}
+}
+
+func TestResult_Metric(t *testing.T) {
+	r := Result{Metrics: []Metric{{1, "ns/op"}, {2, "B/op"}}}
+	if v, ok := r.Metric("B/op"); !ok || v != 2 {
+		t.Errorf("Metric(B/op) = %v, %v", v, ok)
+	}
+	if _, ok := r.Metric("allocs/op"); ok {
+		t.Error("Metric(allocs/op) found a missing metric")
+	}
+}
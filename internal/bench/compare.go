@@ -0,0 +1,156 @@
+package bench
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/testjson"
+)
+
+// Read reads benchmark results from a JSON document written by WriteJSON,
+// from go test -json output or from text in the Go benchmark format.
+func Read(data []byte) ([]Package, error) {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		var doc struct {
+			Packages []Package `json:"packages"`
+		}
+		if err := json.Unmarshal(trimmed, &doc); err == nil && doc.Packages != nil {
+			return doc.Packages, nil
+		}
+		events, err := testjson.Parse(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return FromEvents(events), nil
+	}
+	return ParseText(data), nil
+}
+
+// ParseText parses text in the Go benchmark format. Results follow the
+// configuration of the package named by the latest "pkg:" line.
+func ParseText(data []byte) []Package {
+	var pkgs []Package
+	index := make(map[string]int)
+	current := -1
+	var pending []Config
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if c, ok := parseConfig(line); ok {
+			if c.Key != "pkg" {
+				pending = append(pending, c)
+				continue
+			}
+			i, ok := index[c.Value]
+			if !ok {
+				i = len(pkgs)
+				index[c.Value] = i
+				pkgs = append(pkgs, Package{ImportPath: c.Value, Config: append(pending, c)})
+			}
+			current, pending = i, nil
+			continue
+		}
+		r, ok := ParseLine(line)
+		if !ok {
+			continue
+		}
+		if current < 0 {
+			current = len(pkgs)
+			index[""] = current
+			pkgs = append(pkgs, Package{Config: pending})
+			pending = nil
+		}
+		pkgs[current].Results = append(pkgs[current].Results, r)
+	}
+	return pkgs
+}
+
+// Comparison compares the samples of one metric of a benchmark.
+type Comparison struct {
+	// Package is the import path of the benchmark's package.
+	Package string
+	// Name and Procs identify the benchmark.
+	Name  string
+	Procs int
+	// Unit is the unit of the compared metric.
+	Unit string
+	// Baseline and Current are the samples.
+	Baseline, Current []float64
+	// Delta is the relative change of the median, as 0.1 for +10%.
+	Delta float64
+	// P is the p-value of the Mann-Whitney U test of the samples.
+	P float64
+}
+
+// FullName returns the benchmark name with its GOMAXPROCS suffix.
+func (c Comparison) FullName() string {
+	return Result{Name: c.Name, Procs: c.Procs}.FullName()
+}
+
+// String summarizes the comparison in benchstat's style.
+func (c Comparison) String() string {
+	return fmt.Sprintf("%s %s: %s → %s (%+.2f%%, p=%.3f n=%d+%d)",
+		c.FullName(), c.Unit,
+		formatValue(Median(c.Baseline)), formatValue(Median(c.Current)),
+		c.Delta*100, c.P, len(c.Baseline), len(c.Current))
+}
+
+// formatValue formats a metric value with up to four significant digits.
+func formatValue(v float64) string {
+	return strings.TrimSuffix(fmt.Sprintf("%.4g", v), ".0")
+}
+
+// benchKey identifies a benchmark across result sets.
+type benchKey struct {
+	pkg, name string
+	procs     int
+}
+
+// Compare compares the metrics with the given units of the benchmarks
+// present in both baseline and current, in the order of current.
+func Compare(baseline, current []Package, units []string) []Comparison {
+	samples := func(pkgs []Package) (map[benchKey]map[string][]float64, []benchKey) {
+		out := make(map[benchKey]map[string][]float64)
+		var order []benchKey
+		for _, p := range pkgs {
+			for _, r := range p.Results {
+				k := benchKey{p.ImportPath, r.Name, r.Procs}
+				if out[k] == nil {
+					out[k] = make(map[string][]float64)
+					order = append(order, k)
+				}
+				for _, m := range r.Metrics {
+					out[k][m.Unit] = append(out[k][m.Unit], m.Value)
+				}
+			}
+		}
+		return out, order
+	}
+	old, _ := samples(baseline)
+	cur, order := samples(current)
+
+	var out []Comparison
+	for _, k := range order {
+		for _, unit := range units {
+			x, y := old[k][unit], cur[k][unit]
+			if len(x) == 0 || len(y) == 0 {
+				continue
+			}
+			c := Comparison{
+				Package: k.pkg, Name: k.name, Procs: k.procs, Unit: unit,
+				Baseline: x, Current: y,
+				P: MannWhitneyU(x, y),
+			}
+			if base := Median(x); base != 0 {
+				c.Delta = (Median(y) - base) / base
+			}
+			out = append(out, c)
+		}
+	}
+	return out
+}
@@ -0,0 +1,103 @@
+package bench
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+const benchText = `goos: linux
+goarch: amd64
+pkg: example.com/foo
+BenchmarkJoin-8	100	80 ns/op	16 B/op
+BenchmarkJoin-8	100	82 ns/op	16 B/op
+PASS
+pkg: example.com/bar
+BenchmarkBar	10	5 ns/op
+`
+
+func TestParseText(t *testing.T) {
+	pkgs := ParseText([]byte(benchText))
+	if len(pkgs) != 2 {
+		t.Fatalf("ParseText() = %+v, want two packages", pkgs)
+	}
+	foo := pkgs[0]
+	wantConfig := []Config{{"goos", "linux"}, {"goarch", "amd64"}, {"pkg", "example.com/foo"}}
+	if foo.ImportPath != "example.com/foo" || !reflect.DeepEqual(foo.Config, wantConfig) || len(foo.Results) != 2 {
+		t.Errorf("first package = %+v", foo)
+	}
+	if pkgs[1].ImportPath != "example.com/bar" || len(pkgs[1].Results) != 1 {
+		t.Errorf("second package = %+v", pkgs[1])
+	}
+
+	if got := ParseText([]byte("BenchmarkA\t1\t2 ns/op\n")); len(got) != 1 || got[0].ImportPath != "" {
+		t.Errorf("ParseText() without pkg = %+v", got)
+	}
+}
+
+func TestRead(t *testing.T) {
+	want := FromEvents(benchEvents)
+
+	var doc bytes.Buffer
+	if err := WriteJSON(&doc, want); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	var text bytes.Buffer
+	if err := WriteText(&text, want); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+	events := `{"Action":"output","Package":"example.com/foo","Output":"goos: linux\n"}` + "\n" +
+		`{"Action":"output","Package":"example.com/foo","Output":"goarch: amd64\n"}` + "\n" +
+		`{"Action":"output","Package":"example.com/foo","Output":"pkg: example.com/foo\n"}` + "\n" +
+		`{"Action":"output","Package":"example.com/foo","Test":"BenchmarkJoin","Output":"BenchmarkJoin-4 \t 100\t 81.1 ns/op\n"}` + "\n" +
+		`{"Action":"output","Package":"example.com/foo","Test":"BenchmarkJoin","Output":"BenchmarkJoin-4 \t 100\t 79 ns/op\n"}` + "\n"
+
+	for name, data := range map[string][]byte{
+		"json":   doc.Bytes(),
+		"text":   text.Bytes(),
+		"events": []byte(events),
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := Read(data)
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Read() = %+v, want %+v", got, want)
+			}
+		})
+	}
+
+	if _, err := Read([]byte("{not json")); err == nil {
+		t.Error("Read() of malformed JSON returned no error")
+	}
+}
+
+// samples returns a package with one ns/op result of name per value.
+func samples(name string, values ...float64) Package {
+	p := Package{ImportPath: "example.com/foo"}
+	for _, v := range values {
+		p.Results = append(p.Results, Result{Name: name, Procs: 8, Iterations: 100, Metrics: []Metric{{v, "ns/op"}}})
+	}
+	return p
+}
+
+func TestCompare(t *testing.T) {
+	baseline := []Package{samples("BenchmarkJoin", 100, 101, 99, 98, 102)}
+	current := []Package{
+		samples("BenchmarkJoin", 120, 121, 119, 122, 118),
+		samples("BenchmarkNew", 1, 2, 3),
+	}
+
+	got := Compare(baseline, current, []string{"ns/op", "B/op"})
+	if len(got) != 1 {
+		t.Fatalf("Compare() = %+v, want only the common metric", got)
+	}
+	c := got[0]
+	if c.Name != "BenchmarkJoin" || c.Procs != 8 || c.Unit != "ns/op" || c.Delta != 0.2 || c.P != 2.0/252 {
+		t.Errorf("comparison = %+v", c)
+	}
+	if want := "BenchmarkJoin-8 ns/op: 100 → 120 (+20.00%, p=0.008 n=5+5)"; c.String() != want {
+		t.Errorf("String() = %q, want %q", c.String(), want)
+	}
+}
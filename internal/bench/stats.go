@@ -0,0 +1,136 @@
+package bench
+
+import (
+	"math"
+	"slices"
+)
+
+// Median returns the median of xs, or NaN if xs is empty.
+func Median(xs []float64) float64 {
+	if len(xs) == 0 {
+		return math.NaN()
+	}
+	s := slices.Sorted(slices.Values(xs))
+	if n := len(s); n%2 == 0 {
+		return (s[n/2-1] + s[n/2]) / 2
+	}
+	return s[len(s)/2]
+}
+
+// exactLimit is the largest combined sample size for which MannWhitneyU
+// computes the exact distribution of U.
+const exactLimit = 50
+
+// MannWhitneyU returns the two-sided p-value of the Mann-Whitney U test
+// of whether the samples x and y come from the same distribution, as used
+// by benchstat. The exact distribution is used for small samples without
+// ties, and the normal approximation with tie correction otherwise. It
+// returns 1 when either sample is empty.
+func MannWhitneyU(x, y []float64) float64 {
+	n1, n2 := len(x), len(y)
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+
+	// Rank the pooled samples, averaging the ranks of ties.
+	type sample struct {
+		value float64
+		fromX bool
+	}
+	pooled := make([]sample, 0, n1+n2)
+	for _, v := range x {
+		pooled = append(pooled, sample{v, true})
+	}
+	for _, v := range y {
+		pooled = append(pooled, sample{v, false})
+	}
+	slices.SortFunc(pooled, func(a, b sample) int {
+		switch {
+		case a.value < b.value:
+			return -1
+		case a.value > b.value:
+			return 1
+		}
+		return 0
+	})
+
+	var rankX, tieTerm float64
+	ties := false
+	for i := 0; i < len(pooled); {
+		j := i
+		for j < len(pooled) && pooled[j].value == pooled[i].value {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for _, s := range pooled[i:j] {
+			if s.fromX {
+				rankX += rank
+			}
+		}
+		if t := float64(j - i); t > 1 {
+			ties = true
+			tieTerm += t*t*t - t
+		}
+		i = j
+	}
+	u := rankX - float64(n1*(n1+1))/2
+
+	if !ties && n1+n2 <= exactLimit {
+		return exactP(n1, n2, int(u))
+	}
+
+	// Normal approximation with tie and continuity corrections.
+	n := float64(n1 + n2)
+	mean := float64(n1*n2) / 2
+	variance := float64(n1*n2) / 12 * ((n + 1) - tieTerm/(n*(n-1)))
+	if variance <= 0 {
+		return 1
+	}
+	z := (math.Abs(u-mean) - 0.5) / math.Sqrt(variance)
+	if z < 0 {
+		return 1
+	}
+	return min(1, math.Erfc(z/math.Sqrt2))
+}
+
+// exactP returns the two-sided p-value of U = u for samples of sizes n1
+// and n2 without ties, counting the arrangements with each value of U.
+func exactP(n1, n2, u int) float64 {
+	maxU := n1 * n2
+	// counts[i][j][v] is the number of arrangements of i and j values with
+	// U = v, built up one sample size at a time.
+	prev := make([][]float64, n2+1)
+	for j := range prev {
+		prev[j] = make([]float64, maxU+1)
+		prev[j][0] = 1
+	}
+	for i := 1; i <= n1; i++ {
+		cur := make([][]float64, n2+1)
+		cur[0] = make([]float64, maxU+1)
+		cur[0][0] = 1
+		for j := 1; j <= n2; j++ {
+			cur[j] = make([]float64, maxU+1)
+			for v := 0; v <= i*j; v++ {
+				// The largest value is from x, adding j to U, or from y.
+				if v >= j {
+					cur[j][v] += prev[j][v-j]
+				}
+				cur[j][v] += cur[j-1][v]
+			}
+		}
+		prev = cur
+	}
+
+	counts := prev[n2]
+	var total, lower, upper float64
+	for v, c := range counts {
+		total += c
+		if v <= u {
+			lower += c
+		}
+		if v >= u {
+			upper += c
+		}
+	}
+	return min(1, 2*min(lower, upper)/total)
+}
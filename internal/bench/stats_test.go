@@ -0,0 +1,54 @@
+package bench
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMedian(t *testing.T) {
+	if got := Median([]float64{3, 1, 2}); got != 2 {
+		t.Errorf("Median(odd) = %v, want 2", got)
+	}
+	if got := Median([]float64{4, 1, 3, 2}); got != 2.5 {
+		t.Errorf("Median(even) = %v, want 2.5", got)
+	}
+	if got := Median(nil); !math.IsNaN(got) {
+		t.Errorf("Median(nil) = %v, want NaN", got)
+	}
+}
+
+func TestMannWhitneyU(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y []float64
+		want float64
+	}{
+		// With complete separation the exact p-value is 2/C(n1+n2, n1).
+		{"separated 3+3", []float64{1, 2, 3}, []float64{4, 5, 6}, 2.0 / 20},
+		{"separated 5+5", []float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10}, 2.0 / 252},
+		{"reversed", []float64{6, 7, 8, 9, 10}, []float64{1, 2, 3, 4, 5}, 2.0 / 252},
+		{"interleaved", []float64{1, 3, 5}, []float64{2, 4, 6}, 0.7},
+		{"identical", []float64{1, 1, 1}, []float64{1, 1, 1}, 1},
+		{"empty", nil, []float64{1}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MannWhitneyU(tt.x, tt.y); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("MannWhitneyU() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMannWhitneyU_Ties(t *testing.T) {
+	// Ties select the normal approximation, which must still separate
+	// clearly different samples.
+	x := []float64{10, 10, 11, 11, 12, 12, 10, 11, 12, 10}
+	y := []float64{20, 20, 21, 21, 22, 22, 20, 21, 22, 20}
+	if p := MannWhitneyU(x, y); p >= 0.001 {
+		t.Errorf("MannWhitneyU() = %v, want a significant difference", p)
+	}
+	if p := MannWhitneyU(x, x); p < 0.9 {
+		t.Errorf("MannWhitneyU(x, x) = %v, want no difference", p)
+	}
+}
@@ -0,0 +1,180 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/bench"
+	"github.com/ivuorinen/go-test-sarif-action/internal/compress"
+	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
+	"github.com/ivuorinen/go-test-sarif-action/internal/source"
+)
+
+// regressionRule is reported for benchmarks that got significantly worse
+// than their baseline.
+var regressionRule = sarif.Rule{
+	ID:          "go-bench-regression",
+	Description: "benchmark regression",
+}
+
+// regressionUnits are the benchmark metrics compared with the baseline.
+// Lower values are better for all of them.
+var regressionUnits = []string{"ns/op", "B/op", "allocs/op"}
+
+// BenchmarkBaseline configures the comparison of benchmark results with a
+// baseline.
+type BenchmarkBaseline struct {
+	// File holds the baseline results: a JSON document written with
+	// BenchmarkJSON, go test -json output or benchstat text.
+	File string
+	// Threshold is the change of the median, in percent, beyond which a
+	// significant regression is reported. Zero selects 5.
+	Threshold float64
+	// Alpha is the significance level of the Mann-Whitney U test. Zero
+	// selects 0.05.
+	Alpha float64
+}
+
+// benchmarkSets returns the benchmark results of each go test input,
+// with the paths of the inputs they were read from.
+func benchmarkSets(inputs []input) (sets [][]bench.Package, paths []string) {
+	for _, in := range inputs {
+		if in.Format == formatTestJSON || in.Format == formatBazelTestlogs {
+			sets = append(sets, bench.FromEvents(in.Events))
+			paths = append(paths, in.Path)
+		}
+	}
+	return sets, paths
+}
+
+// writeBenchmarks writes the benchmark results of the inputs to the files
+// selected by opts, if any.
+func writeBenchmarks(inputs []input, opts ConvertOptions) error {
+	if opts.BenchmarkText == "" && opts.BenchmarkJSON == "" {
+		return nil
+	}
+	sets, _ := benchmarkSets(inputs)
+	pkgs := bench.Merge(sets...)
+
+	for _, out := range []struct {
+		file  string
+		write func(io.Writer, []bench.Package) error
+	}{
+		{opts.BenchmarkText, bench.WriteText},
+		{opts.BenchmarkJSON, bench.WriteJSON},
+	} {
+		if out.file == "" {
+			continue
+		}
+		var buf bytes.Buffer
+		if err := out.write(&buf, pkgs); err != nil {
+			return err
+		}
+		if err := os.WriteFile(out.file, buf.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("writing benchmarks: %w", err)
+		}
+	}
+	return nil
+}
+
+// readBaseline reads the baseline benchmark results from file.
+func readBaseline(file string) ([]bench.Package, error) {
+	rc, err := compress.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rc.Close() }()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+	pkgs, err := bench.Read(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return pkgs, nil
+}
+
+// addRegressionResults compares the benchmark results of the inputs with
+// the baseline and reports the metrics that got significantly worse by
+// more than the threshold.
+func addRegressionResults(report *sarif.Report, inputs []input, opts ConvertOptions) error {
+	cfg := opts.Baseline
+	if cfg.File == "" {
+		return nil
+	}
+	baseline, err := readBaseline(cfg.File)
+	if err != nil {
+		return err
+	}
+	threshold, alpha := cfg.Threshold, cfg.Alpha
+	if threshold == 0 {
+		threshold = 5
+	}
+	if alpha == 0 {
+		alpha = 0.05
+	}
+
+	sets, paths := benchmarkSets(inputs)
+	b := &reportBuilder{report: report, seen: make(map[resultKey]int)}
+	resolver := source.NewResolver(opts.SourceRoot)
+	for _, c := range bench.Compare(baseline, bench.Merge(sets...), regressionUnits) {
+		if c.P >= alpha || c.Delta*100 <= threshold {
+			continue
+		}
+		b.addRule(regressionRule)
+		result := regressionResult(c, threshold, resolver)
+		for i, pkgs := range sets {
+			if hasBenchmark(pkgs, c) {
+				b.add(result, paths[i])
+			}
+		}
+	}
+	return nil
+}
+
+// hasBenchmark reports whether pkgs hold results of the compared
+// benchmark.
+func hasBenchmark(pkgs []bench.Package, c bench.Comparison) bool {
+	for _, p := range pkgs {
+		if p.ImportPath != c.Package {
+			continue
+		}
+		for _, r := range p.Results {
+			if r.Name == c.Name && r.Procs == c.Procs {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// regressionResult reports a benchmark metric that regressed, located at
+// the benchmark function.
+func regressionResult(c bench.Comparison, threshold float64, resolver *source.Resolver) sarif.Result {
+	result := sarif.Result{
+		RuleID:   regressionRule.ID,
+		Level:    "warning",
+		Message:  fmt.Sprintf("%s, beyond the %g%% threshold", c, threshold),
+		Location: &sarif.LogicalLocation{Module: c.Package, Function: c.FullName()},
+		Properties: map[string]any{
+			"metric":          c.Unit,
+			"delta":           c.Delta,
+			"pValue":          c.P,
+			"baseline":        bench.Median(c.Baseline),
+			"current":         bench.Median(c.Current),
+			"baselineSamples": len(c.Baseline),
+			"samples":         len(c.Current),
+		},
+	}
+	if pos := findTestFunc(resolver, c.Package, c.Name); pos != nil {
+		result.PhysicalLocation = &sarif.PhysicalLocation{
+			URI:       resolver.URI(pos.File),
+			StartLine: pos.Line,
+		}
+	}
+	return result
+}
@@ -0,0 +1,126 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteBenchmarks(t *testing.T) {
+	dir := t.TempDir()
+	shard := func(value string) string {
+		return `{"Action":"output","Package":"example.com/foo","Output":"pkg: example.com/foo\n"}` + "\n" +
+			`{"Action":"run","Package":"example.com/foo","Test":"BenchmarkJoin"}` + "\n" +
+			`{"Action":"output","Package":"example.com/foo","Test":"BenchmarkJoin","Output":"BenchmarkJoin-8 \t 100\t ` + value + ` ns/op\t 16 B/op\n"}` + "\n" +
+			`{"Action":"pass","Package":"example.com/foo"}` + "\n"
+	}
+	var inputs []string
+	for i, value := range []string{"81.10", "79.5"} {
+		path := filepath.Join(dir, "bench-"+string(rune('a'+i))+".json")
+		if err := os.WriteFile(path, []byte(shard(value)), 0o600); err != nil {
+			t.Fatalf("failed to write input: %v", err)
+		}
+		inputs = append(inputs, path)
+	}
+
+	opts := DefaultConvertOptions()
+	opts.BenchmarkText = filepath.Join(dir, "bench.txt")
+	opts.BenchmarkJSON = filepath.Join(dir, "bench.json")
+	if err := ConvertFilesToSARIF(inputs, filepath.Join(dir, "out.sarif"), opts); err != nil {
+		t.Fatalf("ConvertFilesToSARIF() error = %v", err)
+	}
+
+	text, err := os.ReadFile(opts.BenchmarkText)
+	if err != nil {
+		t.Fatalf("failed to read benchmark text: %v", err)
+	}
+	want := "pkg: example.com/foo\n" +
+		"BenchmarkJoin-8\t100\t81.1 ns/op\t16 B/op\n" +
+		"BenchmarkJoin-8\t100\t79.5 ns/op\t16 B/op\n"
+	if string(text) != want {
+		t.Errorf("benchmark text = %q, want %q", text, want)
+	}
+
+	doc, err := os.ReadFile(opts.BenchmarkJSON)
+	if err != nil {
+		t.Fatalf("failed to read benchmark JSON: %v", err)
+	}
+	if !strings.Contains(string(doc), `"unit": "B/op"`) {
+		t.Errorf("benchmark JSON = %s", doc)
+	}
+}
+
+func TestWriteBenchmarks_Disabled(t *testing.T) {
+	if err := writeBenchmarks(nil, ConvertOptions{}); err != nil {
+		t.Errorf("writeBenchmarks() error = %v", err)
+	}
+}
+
+// benchRuns returns go test events with one BenchmarkJoin result per
+// ns/op value.
+func benchRuns(values ...string) string {
+	var b strings.Builder
+	for _, v := range values {
+		b.WriteString(`{"Action":"output","Package":"example.com/app/foo","Test":"BenchmarkJoin","Output":"BenchmarkJoin-8 \t 100\t ` +
+			v + ` ns/op\t 16 B/op\n"}` + "\n")
+	}
+	return b.String()
+}
+
+func TestAddRegressionResults(t *testing.T) {
+	resolver := writeTestModule(t, map[string]string{
+		"foo/foo_test.go": "package foo\n\nimport \"testing\"\n\nfunc BenchmarkJoin(b *testing.B) {}\n",
+	})
+	dir := t.TempDir()
+	baseline := filepath.Join(dir, "old.txt")
+	old := "pkg: example.com/app/foo\n"
+	for _, v := range []string{"100", "101", "99", "98", "102"} {
+		old += "BenchmarkJoin-8\t100\t" + v + " ns/op\t16 B/op\n"
+	}
+	current := filepath.Join(dir, "new.json")
+	files := map[string]string{baseline: old, current: benchRuns("120", "121", "119", "122", "118")}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	inputs, err := readInputs([]string{current})
+	if err != nil {
+		t.Fatalf("readInputs() error = %v", err)
+	}
+	report := buildReport(inputs, ConvertOptions{})
+	opts := ConvertOptions{SourceRoot: resolver.Root(), Baseline: BenchmarkBaseline{File: baseline}}
+	if err := addRegressionResults(report, inputs, opts); err != nil {
+		t.Fatalf("addRegressionResults() error = %v", err)
+	}
+
+	if len(report.Results) != 1 {
+		t.Fatalf("results = %+v, want one ns/op regression", report.Results)
+	}
+	r := report.Results[0]
+	want := "BenchmarkJoin-8 ns/op: 100 → 120 (+20.00%, p=0.008 n=5+5), beyond the 5% threshold"
+	if r.RuleID != regressionRule.ID || r.Level != "warning" || r.Message != want {
+		t.Errorf("result = %s %s: %q", r.RuleID, r.Level, r.Message)
+	}
+	if r.Properties["metric"] != "ns/op" || r.Properties["delta"] != 0.2 ||
+		r.Properties["baselineSamples"] != 5 || r.Properties["samples"] != 5 {
+		t.Errorf("properties = %v", r.Properties)
+	}
+	if loc := r.PhysicalLocation; loc == nil || loc.URI != "foo/foo_test.go" || loc.StartLine != 5 {
+		t.Errorf("location = %+v, want the benchmark function", loc)
+	}
+
+	// A higher threshold accepts the change.
+	report = buildReport(inputs, ConvertOptions{})
+	opts.Baseline.Threshold = 25
+	if err := addRegressionResults(report, inputs, opts); err != nil || len(report.Results) != 0 {
+		t.Errorf("results = %+v, %v, want none under a 25%% threshold", report.Results, err)
+	}
+
+	opts.Baseline.File = filepath.Join(dir, "missing.json")
+	if err := addRegressionResults(report, inputs, opts); err == nil {
+		t.Error("addRegressionResults() with a missing baseline returned no error")
+	}
+}
@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"strings"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/buildjson"
+	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
+	"github.com/ivuorinen/go-test-sarif-action/internal/source"
+)
+
+// buildRule is reported for packages that failed to build, as read from
+// go build -json output.
+var buildRule = sarif.Rule{
+	ID:          "go-build-error",
+	Description: "go build error",
+}
+
+// addBuildResults adds the failed builds of go build -json output to the
+// report, one result per compiler diagnostic. A build that failed without
+// a located diagnostic, such as a missing package, is reported once with
+// its output.
+func addBuildResults(b *reportBuilder, in input, resolver *source.Resolver) {
+	for _, f := range buildjson.Failures(in.Build) {
+		b.addRule(buildRule)
+		pkg := f.Package()
+
+		diags := buildjson.Diagnostics(f.Output)
+		for _, d := range diags {
+			b.add(sarif.Result{
+				RuleID:   buildRule.ID,
+				Level:    "error",
+				Message:  d.Message,
+				Location: &sarif.LogicalLocation{Module: pkg},
+				PhysicalLocation: &sarif.PhysicalLocation{
+					URI:         resolver.URI(d.File),
+					StartLine:   d.Line,
+					StartColumn: d.Column,
+				},
+			}, in.Path)
+		}
+		if len(diags) > 0 {
+			continue
+		}
+
+		message := strings.TrimSpace(strings.Join(f.Output, ""))
+		if message == "" {
+			message = "package " + pkg + " failed to build"
+		}
+		b.add(sarif.Result{
+			RuleID:   buildRule.ID,
+			Level:    "error",
+			Message:  message,
+			Location: &sarif.LogicalLocation{Module: pkg},
+		}, in.Path)
+	}
+}
@@ -0,0 +1,34 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/buildjson"
+)
+
+func TestAddBuildResults(t *testing.T) {
+	in := input{Path: "build.json", Format: formatBuildJSON, Build: []buildjson.BuildEvent{
+		{ImportPath: testPkg, Action: "build-output", Output: "# example.com/app/foo\n"},
+		{ImportPath: testPkg, Action: "build-output", Output: "foo/foo.go:4:9: undefined: bar\n"},
+		{ImportPath: testPkg, Action: "build-fail"},
+		{ImportPath: "example.com/app/ok", Action: "build-output", Output: "# example.com/app/ok\n"},
+		{ImportPath: "example.com/app/gone", Action: "build-output", Output: "package example.com/app/gone is not in std\n"},
+		{ImportPath: "example.com/app/gone", Action: "build-fail"},
+	}}
+
+	report := buildReport([]input{in}, ConvertOptions{SourceRoot: t.TempDir()})
+
+	if len(report.Results) != 2 {
+		t.Fatalf("got %d results, want 2: %+v", len(report.Results), report.Results)
+	}
+	r := report.Results[0]
+	if r.RuleID != buildRule.ID || r.Message != "undefined: bar" || r.Location.Module != testPkg {
+		t.Errorf("result = %+v", r)
+	}
+	if loc := r.PhysicalLocation; loc == nil || loc.URI != "foo/foo.go" || loc.StartLine != 4 || loc.StartColumn != 9 {
+		t.Errorf("PhysicalLocation = %+v, want foo/foo.go:4:9", loc)
+	}
+	if r := report.Results[1]; r.Message != "package example.com/app/gone is not in std" || r.PhysicalLocation != nil {
+		t.Errorf("result = %+v, want the unlocated build output", r)
+	}
+}
@@ -0,0 +1,124 @@
+// Package buildjson provides parsing utilities for go build -json output.
+package buildjson
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// BuildEvent captures the fields of a go build -json event. The same
+// events appear in go test -json output when a test binary fails to
+// build.
+type BuildEvent struct {
+	// ImportPath identifies the build, as in "example.com/foo" or
+	// "example.com/foo [example.com/foo.test]".
+	ImportPath string `json:"ImportPath"`
+	// Action is the event type, build-output or build-fail.
+	Action string `json:"Action"`
+	// Output is a line of compiler or linker output.
+	Output string `json:"Output,omitempty"`
+}
+
+// Parse reads and parses go build -json output from r.
+// Returns an error with line number if any line contains invalid JSON.
+func Parse(r io.Reader) ([]BuildEvent, error) {
+	var events []BuildEvent
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+		var event BuildEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("line %d: invalid JSON: %w", lineNum, err)
+		}
+		events = append(events, event)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// Failure is a build that failed, with its output.
+type Failure struct {
+	// ImportPath identifies the build.
+	ImportPath string
+	// Output holds the output lines of the build.
+	Output []string
+}
+
+// Package returns the import path of the package that failed to build,
+// without the test binary suffix of ImportPath.
+func (f Failure) Package() string {
+	pkg, _, _ := strings.Cut(f.ImportPath, " ")
+	return pkg
+}
+
+// Failures returns the failed builds of events, in order of failure.
+func Failures(events []BuildEvent) []Failure {
+	output := make(map[string][]string)
+	var failures []Failure
+	for _, e := range events {
+		switch e.Action {
+		case "build-output":
+			output[e.ImportPath] = append(output[e.ImportPath], e.Output)
+		case "build-fail":
+			failures = append(failures, Failure{ImportPath: e.ImportPath, Output: output[e.ImportPath]})
+		}
+	}
+	return failures
+}
+
+// Diagnostic is a "file.go:line:col: message" line printed by the
+// compiler or by go vet.
+type Diagnostic struct {
+	// File is the source file as printed, usually relative to the
+	// directory the go command ran in.
+	File string
+	// Line and Column locate the diagnostic; Column is 0 if not printed.
+	Line, Column int
+	// Message is the diagnostic text, including continuation lines.
+	Message string
+}
+
+var diagnosticRe = regexp.MustCompile(`^(\S.*?\.go):(\d+)(?::(\d+))?: (.*)$`)
+
+// ParseDiagnostic parses a single diagnostic line.
+func ParseDiagnostic(line string) (Diagnostic, bool) {
+	m := diagnosticRe.FindStringSubmatch(strings.TrimRight(line, "\r\n"))
+	if m == nil {
+		return Diagnostic{}, false
+	}
+	d := Diagnostic{File: m[1], Message: m[4]}
+	d.Line, _ = strconv.Atoi(m[2])
+	d.Column, _ = strconv.Atoi(m[3])
+	return d, true
+}
+
+// Diagnostics extracts the diagnostics from build output. Indented lines
+// continue the preceding diagnostic; other lines are ignored.
+func Diagnostics(output []string) []Diagnostic {
+	var diags []Diagnostic
+	for _, line := range output {
+		line = strings.TrimRight(line, "\r\n")
+		if strings.HasPrefix(line, "\t") {
+			if len(diags) > 0 {
+				diags[len(diags)-1].Message += "\n" + strings.TrimSpace(line)
+			}
+			continue
+		}
+		if d, ok := ParseDiagnostic(line); ok {
+			diags = append(diags, d)
+		}
+	}
+	return diags
+}
@@ -0,0 +1,75 @@
+package buildjson
+
+import (
+	"strings"
+	"testing"
+)
+
+const buildOutput = `{"ImportPath":"example.com/foo","Action":"build-output","Output":"# example.com/foo\n"}
+{"ImportPath":"example.com/foo","Action":"build-output","Output":"foo/foo.go:4:9: cannot use \"x\" (untyped string constant) as int value in return statement\n"}
+{"ImportPath":"example.com/foo","Action":"build-output","Output":"foo/foo.go:7:2: undefined: bar\n"}
+{"ImportPath":"example.com/foo","Action":"build-fail"}
+{"ImportPath":"example.com/ok","Action":"build-output","Output":"# example.com/ok\n"}
+{"ImportPath":"example.com/bar [example.com/bar.test]","Action":"build-output","Output":"example.com/bar: no non-test Go files in /src/bar\n"}
+{"ImportPath":"example.com/bar [example.com/bar.test]","Action":"build-fail"}
+`
+
+func TestParse(t *testing.T) {
+	events, err := Parse(strings.NewReader(buildOutput))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(events) != 7 {
+		t.Fatalf("got %d events, want 7", len(events))
+	}
+	if e := events[0]; e.ImportPath != "example.com/foo" || e.Action != "build-output" || e.Output != "# example.com/foo\n" {
+		t.Errorf("event = %+v", e)
+	}
+}
+
+func TestParse_MalformedJSON(t *testing.T) {
+	_, err := Parse(strings.NewReader("{\"Action\":\"build-fail\"}\n{broken\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Parse error = %v, want one naming line 2", err)
+	}
+}
+
+func TestFailures(t *testing.T) {
+	events, _ := Parse(strings.NewReader(buildOutput))
+
+	failures := Failures(events)
+	if len(failures) != 2 {
+		t.Fatalf("got %d failures, want 2: %+v", len(failures), failures)
+	}
+	if f := failures[0]; f.Package() != "example.com/foo" || len(f.Output) != 3 {
+		t.Errorf("failure = %+v", f)
+	}
+	if got := failures[1].Package(); got != "example.com/bar" {
+		t.Errorf("Package() = %q, want the import path without the test suffix", got)
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	output := []string{
+		"# example.com/foo\n",
+		"foo/foo.go:4:9: cannot use x\n",
+		"\thave int\n",
+		"\twant string\n",
+		"foo/foo.go:7: line only\n",
+		"too many errors\n",
+	}
+
+	want := []Diagnostic{
+		{File: "foo/foo.go", Line: 4, Column: 9, Message: "cannot use x\nhave int\nwant string"},
+		{File: "foo/foo.go", Line: 7, Message: "line only"},
+	}
+	got := Diagnostics(output)
+	if len(got) != len(want) {
+		t.Fatalf("Diagnostics() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("diagnostic %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
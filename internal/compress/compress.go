@@ -0,0 +1,115 @@
+// Package compress provides transparent handling of compressed input and output.
+package compress
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+// Stdin is the path that selects standard input in Open.
+const Stdin = "-"
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// extensions are the file extensions of the formats recognized by
+// NewReader.
+var extensions = []string{".gz", ".zst"}
+
+// ErrZstdUnsupported is returned for zstd-compressed input, which cannot be
+// decoded with the standard library.
+var ErrZstdUnsupported = errors.New(
+	"zstd-compressed input is not supported; decompress it with `zstd -dc` and pipe it to standard input (-)")
+
+// Open opens path for reading and transparently decompresses gzip content,
+// detected by its magic bytes rather than the file extension. The path "-"
+// reads from standard input.
+func Open(path string) (io.ReadCloser, error) {
+	if path == Stdin {
+		return NewReader(io.NopCloser(os.Stdin))
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := NewReader(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return rc, nil
+}
+
+// NewReader wraps rc so that gzip-compressed content is decompressed while
+// reading. Uncompressed content is passed through unchanged. Closing the
+// returned reader closes rc.
+func NewReader(rc io.ReadCloser) (io.ReadCloser, error) {
+	br := bufio.NewReader(rc)
+	magic, err := br.Peek(len(zstdMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return &readCloser{Reader: zr, closers: []io.Closer{zr, rc}}, nil
+	case bytes.HasPrefix(magic, zstdMagic):
+		return nil, ErrZstdUnsupported
+	}
+
+	return &readCloser{Reader: br, closers: []io.Closer{rc}}, nil
+}
+
+// TrimExt returns path without the extension of a compression format
+// recognized by NewReader, if it has one.
+func TrimExt(path string) string {
+	for _, ext := range extensions {
+		if trimmed, ok := strings.CutSuffix(path, ext); ok {
+			return trimmed
+		}
+	}
+	return path
+}
+
+// IsGzipPath reports whether path names a gzip-compressed file.
+func IsGzipPath(path string) bool {
+	return strings.HasSuffix(path, ".gz")
+}
+
+// Gzip compresses data with gzip.
+func Gzip(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type readCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r *readCloser) Close() error {
+	var errs []error
+	for _, c := range r.closers {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,115 @@
+package compress
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testContent = `{"Action":"pass","Package":"example.com/foo"}` + "\n"
+
+func readAll(t *testing.T, path string) (string, error) {
+	t.Helper()
+	rc, err := Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = rc.Close() }()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return string(data), nil
+}
+
+func TestOpen_Plain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input.json")
+	if err := os.WriteFile(path, []byte(testContent), 0o600); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	got, err := readAll(t, path)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	if got != testContent {
+		t.Errorf("content = %q, want %q", got, testContent)
+	}
+}
+
+func TestOpen_GzipDetectedByMagic(t *testing.T) {
+	data, err := Gzip([]byte(testContent))
+	if err != nil {
+		t.Fatalf("Gzip returned error: %v", err)
+	}
+
+	// No .gz extension: detection must rely on the content.
+	path := filepath.Join(t.TempDir(), "input.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	got, err := readAll(t, path)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	if got != testContent {
+		t.Errorf("content = %q, want %q", got, testContent)
+	}
+}
+
+func TestOpen_Zstd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input.json.zst")
+	if err := os.WriteFile(path, []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00}, 0o600); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	if _, err := readAll(t, path); !errors.Is(err, ErrZstdUnsupported) {
+		t.Errorf("Open error = %v, want %v", err, ErrZstdUnsupported)
+	}
+}
+
+func TestOpen_Empty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.json")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	got, err := readAll(t, path)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	if got != "" {
+		t.Errorf("content = %q, want empty", got)
+	}
+}
+
+func TestTrimExt(t *testing.T) {
+	tests := map[string]string{
+		"foo.test.json":     "foo.test.json",
+		"foo.test.json.gz":  "foo.test.json",
+		"foo.test.zst":      "foo.test",
+		"foo.test.json.zst": "foo.test.json",
+	}
+	for path, want := range tests {
+		if got := TrimExt(path); got != want {
+			t.Errorf("TrimExt(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestIsGzipPath(t *testing.T) {
+	tests := map[string]bool{
+		"out.sarif":    false,
+		"out.sarif.gz": true,
+		"gz.sarif":     false,
+	}
+	for path, want := range tests {
+		if got := IsGzipPath(path); got != want {
+			t.Errorf("IsGzipPath(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
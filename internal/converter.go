@@ -5,8 +5,16 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/ivuorinen/go-test-sarif-action/internal/bazel"
+	"github.com/ivuorinen/go-test-sarif-action/internal/buildjson"
+	"github.com/ivuorinen/go-test-sarif-action/internal/compress"
+	"github.com/ivuorinen/go-test-sarif-action/internal/coverage"
+	"github.com/ivuorinen/go-test-sarif-action/internal/ginkgojson"
+	"github.com/ivuorinen/go-test-sarif-action/internal/recognize"
 	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
+	"github.com/ivuorinen/go-test-sarif-action/internal/source"
 	"github.com/ivuorinen/go-test-sarif-action/internal/testjson"
+	"github.com/ivuorinen/go-test-sarif-action/internal/vetjson"
 )
 
 // ConvertOptions configures the conversion behavior.
@@ -15,6 +23,42 @@ type ConvertOptions struct {
 	SARIFVersion sarif.Version
 	// Pretty enables indented JSON output for readability.
 	Pretty bool
+	// Compress gzip-compresses the output. Output files ending in ".gz"
+	// are always compressed.
+	Compress bool
+	// Package is assigned to events without a Package field, as produced
+	// by running a compiled test binary through go tool test2json.
+	Package string
+	// PackageByInput overrides Package for individual inputs, keyed by
+	// input path or file name.
+	PackageByInput map[string]string
+	// Attributes maps test attributes onto result fields.
+	Attributes AttrMapping
+	// SourceRoot is the root of the source tree that file locations are
+	// resolved against. It defaults to the working directory.
+	SourceRoot string
+	// ArtifactDirPattern locates the artifact directory of tests that do
+	// not report one. The {package} and {test} placeholders are replaced
+	// with the package import path and the test name.
+	ArtifactDirPattern string
+	// Recognizers extract assertion details from the output of failed
+	// tests. Nil selects recognize.Default.
+	Recognizers []recognize.Recognizer
+	// Slow selects the tests reported as slow.
+	Slow SlowOptions
+	// BenchmarkText names a file to write the benchmark results of the
+	// inputs to, in the format read by benchstat.
+	BenchmarkText string
+	// BenchmarkJSON names a file to write the benchmark results of the
+	// inputs to as JSON.
+	BenchmarkJSON string
+	// Baseline selects benchmark results to report regressions against.
+	Baseline BenchmarkBaseline
+	// Coverage selects the files and packages reported for low coverage.
+	Coverage CoverageOptions
+	// Rerun names a file to write the commands rerunning the failed tests
+	// to: a JSON plan when it ends in ".json", a shell script otherwise.
+	Rerun string
 }
 
 // DefaultConvertOptions returns options with sensible defaults.
@@ -25,16 +69,69 @@ func DefaultConvertOptions() ConvertOptions {
 	}
 }
 
+// input holds the data parsed from a single input file.
+type input struct {
+	// Path is the file the data was read from.
+	Path string
+	// Format is the kind of output the file holds.
+	Format inputFormat
+	// Events are the parsed go test JSON events, in file order.
+	Events []testjson.TestEvent
+	// Build are the parsed go build JSON events, in file order.
+	Build []buildjson.BuildEvent
+	// Vet are the parsed go vet JSON findings.
+	Vet []vetjson.Package
+	// Ginkgo are the suite reports of a parsed Ginkgo JSON report.
+	Ginkgo []ginkgojson.Report
+	// Coverage is a parsed coverage profile.
+	Coverage *coverage.Profile
+	// Target is the Bazel test target the events were read from, if any.
+	Target *bazel.Target
+}
+
 // ConvertToSARIF converts Go test JSON events to SARIF format.
 func ConvertToSARIF(inputFile, outputFile string, opts ConvertOptions) error {
-	// Parse go test JSON
-	events, err := testjson.ParseFile(inputFile)
+	return ConvertFilesToSARIF([]string{inputFile}, outputFile, opts)
+}
+
+// ConvertFilesToSARIF converts the Go toolchain JSON output of several
+// input files into a single SARIF report. Each input may hold go test,
+// go build or go vet JSON output, a Ginkgo JSON report or a coverage
+// profile, detected by its content. Directories are read as
+// bazel-testlogs trees. Identical failures reported by more than one
+// input are merged into one result.
+func ConvertFilesToSARIF(inputFiles []string, outputFile string, opts ConvertOptions) error {
+	// Parse the inputs
+	inputs, err := readInputs(inputFiles)
 	if err != nil {
 		return err
 	}
+	resolver := source.NewResolver(opts.SourceRoot)
+	for i := range inputs {
+		pkg := packageFor(inputs[i].Path, opts)
+		if t := inputs[i].Target; t != nil {
+			pkg = bazelImportPath(*t, resolver)
+		}
+		fillPackage(&inputs[i], pkg)
+	}
+	if err := writeBenchmarks(inputs, opts); err != nil {
+		return err
+	}
+	if err := writeCoverageSummary(inputs, opts); err != nil {
+		return err
+	}
+	if err := writeRerun(inputs, opts); err != nil {
+		return err
+	}
 
 	// Build internal SARIF model
-	report := buildReport(events)
+	report := buildReport(inputs, opts)
+	if err := addRegressionResults(report, inputs, opts); err != nil {
+		return err
+	}
+	if err := addDiffCoverageResults(report, inputs, opts); err != nil {
+		return err
+	}
 
 	// Serialize to requested version
 	data, err := sarif.Serialize(report, opts.SARIFVersion, opts.Pretty)
@@ -42,6 +139,12 @@ func ConvertToSARIF(inputFile, outputFile string, opts ConvertOptions) error {
 		return err
 	}
 
+	if opts.Compress || compress.IsGzipPath(outputFile) {
+		if data, err = compress.Gzip(data); err != nil {
+			return err
+		}
+	}
+
 	// Write output
 	if err := os.WriteFile(outputFile, data, 0o644); err != nil {
 		return err
@@ -51,30 +154,211 @@ func ConvertToSARIF(inputFile, outputFile string, opts ConvertOptions) error {
 	return nil
 }
 
-func buildReport(events []testjson.TestEvent) *sarif.Report {
-	report := &sarif.Report{
-		ToolName:    "go-test-sarif",
-		ToolInfoURI: "https://golang.org/cmd/go/#hdr-Test_packages",
-		Rules: []sarif.Rule{{
-			ID:          "go-test-failure",
-			Description: "go test failure",
-		}},
+// testKey identifies a test, or a package when test is empty.
+type testKey struct {
+	pkg  string
+	test string
+}
+
+// failureRule is reported for failed tests and packages.
+var failureRule = sarif.Rule{
+	ID:          "go-test-failure",
+	Description: "go test failure",
+}
+
+// resultKey identifies results that are duplicates of each other.
+type resultKey struct {
+	ruleID  string
+	pkg     string
+	test    string
+	message string
+}
+
+// reportBuilder accumulates results, merging duplicates reported by
+// several inputs.
+type reportBuilder struct {
+	report *sarif.Report
+	seen   map[resultKey]int
+}
+
+// addRule adds rule to the report unless it is already present.
+func (b *reportBuilder) addRule(rule sarif.Rule) {
+	for _, r := range b.report.Rules {
+		if r.ID == rule.ID {
+			return
+		}
+	}
+	b.report.Rules = append(b.report.Rules, rule)
+}
+
+// add appends result as reported by the input at path. It returns false,
+// recording path on the existing result, if an identical result exists.
+func (b *reportBuilder) add(result sarif.Result, path string) bool {
+	key := resultKey{ruleID: result.RuleID, message: result.Message}
+	if result.Location != nil {
+		key.pkg, key.test = result.Location.Module, result.Location.Function
 	}
+	if idx, ok := b.seen[key]; ok {
+		addInput(&b.report.Results[idx], path)
+		return false
+	}
+
+	addInput(&result, path)
+	b.seen[key] = len(b.report.Results)
+	b.report.Results = append(b.report.Results, result)
+	return true
+}
+
+// addReproducible adds result like add and, if it is new, gives the
+// command cmd reproducing it, if any, in its message. Duplicates are
+// found before the command is appended since it differs between runs of
+// the same failure, as with the seeds of shards run with -shuffle=on.
+func (b *reportBuilder) addReproducible(result sarif.Result, path, cmd string) bool {
+	if !b.add(result, path) {
+		return false
+	}
+	if cmd != "" {
+		appendReproduce(&b.report.Results[len(b.report.Results)-1], cmd)
+	}
+	return true
+}
+
+func buildReport(inputs []input, opts ConvertOptions) *sarif.Report {
+	b := &reportBuilder{
+		report: &sarif.Report{
+			ToolName:    "go-test-sarif",
+			ToolInfoURI: "https://golang.org/cmd/go/#hdr-Test_packages",
+			Rules:       []sarif.Rule{failureRule},
+		},
+		seen: make(map[resultKey]int),
+	}
+	resolver := source.NewResolver(opts.SourceRoot)
+	recognizers := opts.Recognizers
+	if recognizers == nil {
+		recognizers = recognize.Default()
+	}
+
+	var times timings
+	for _, in := range inputs {
+		switch in.Format {
+		case formatVetJSON:
+			addVetJSONResults(b, in, resolver)
+			continue
+		case formatBuildJSON:
+			addBuildResults(b, in, resolver)
+			continue
+		case formatGinkgoJSON:
+			addGinkgoResults(b, in, resolver)
+			continue
+		case formatCoverProfile:
+			continue
+		}
+
+		cases := collectTests(in.Events)
+		times.add(cases, in.Path)
+		counts := countOutcomes(cases)
+		flags := collectRunFlags(in.Events)
+		explained := failedOnlyByFlakyTests(cases, counts)
+		flaky := make(map[testKey][]*testCase)
+		var flakyKeys []testKey
+		for _, c := range cases {
+			if c.Action != "fail" || (c.test == "" && c.pkg == "") {
+				continue
+			}
+
+			if c.FailedBuild {
+				if diags, ok := parseVetOutput(c.BuildOutput); ok {
+					for _, d := range diags {
+						b.addRule(vetAnalyzerRule(d.Analyzer))
+						b.add(vetResult(d, c.pkg, resolver), in.Path)
+					}
+					continue
+				}
+			}
+			if explained[c.testKey] && !c.FailedBuild {
+				continue
+			}
+			if counts[c.testKey].flaky() {
+				if flaky[c.testKey] == nil {
+					flakyKeys = append(flakyKeys, c.testKey)
+				}
+				flaky[c.testKey] = append(flaky[c.testKey], c)
+				continue
+			}
+			if c.test == "" {
+				if f, ok := findRuntimeFatal(c, cases); ok {
+					b.addRule(fatalRule)
+					for _, result := range fatalResults(f, c.pkg, resolver) {
+						cmd := applyRunFlags(&result, c.pkg, "", flags[c.pkg])
+						b.addReproducible(result, in.Path, cmd)
+					}
+					continue
+				}
+			}
+			if leaks, ok := findGoroutineLeaks(c.outputText()); ok {
+				b.addRule(leakRule)
+				for _, result := range leakResults(leaks, c.pkg, c.test, resolver) {
+					cmd := applyRunFlags(&result, c.pkg, c.test, flags[c.pkg])
+					b.addReproducible(result, in.Path, cmd)
+				}
+				continue
+			}
 
-	for _, e := range events {
-		if e.Action == "fail" && (e.Test != "" || e.Package != "") {
 			result := sarif.Result{
-				RuleID:  "go-test-failure",
+				RuleID:  failureRule.ID,
 				Level:   "error",
-				Message: e.Output,
+				Message: c.message(),
 			}
 			result.Location = &sarif.LogicalLocation{
-				Module:   e.Package,
-				Function: e.Test,
+				Module:   c.pkg,
+				Function: c.test,
+			}
+			switch {
+			case c.test == "":
+				f := classifyPackageFailure(c, cases, resolver)
+				describePackageFailure(&result, f, c.outputText(), resolver)
+			case isExample(c.test):
+				describeExampleFailure(&result, c, resolver)
+			default:
+				describeAssertionFailure(&result, c, recognizers, resolver)
+			}
+
+			b.addRule(applyAttributes(&result, failureRule, c.Attrs, opts.Attributes))
+			cmd := applyRunFlags(&result, c.pkg, c.test, flags[c.pkg])
+			var artifacts []sarif.Artifact
+			if c.test != "" {
+				if dir := artifactDir(c.ArtifactDir, opts.ArtifactDirPattern, c.testKey); dir != "" {
+					result.Attachments, artifacts = collectArtifacts(dir, c.test, resolver)
+				}
+			}
+			if b.addReproducible(result, in.Path, cmd) {
+				addArtifacts(b.report, artifacts)
 			}
-			report.Results = append(report.Results, result)
+		}
+
+		for _, k := range flakyKeys {
+			result := flakyResult(flaky[k], counts[k], recognizers, resolver)
+			b.addRule(applyAttributes(&result, flakyRule, flaky[k][0].Attrs, opts.Attributes))
+			cmd := applyRunFlags(&result, k.pkg, k.test, flags[k.pkg])
+			b.addReproducible(result, in.Path, cmd)
 		}
 	}
+	addSlowResults(b, times, opts.Slow, resolver)
+	addCoverageResults(b, inputs, opts.Coverage, resolver)
+
+	return b.report
+}
 
-	return report
+// addInput records path as one of the inputs that reported the result.
+func addInput(result *sarif.Result, path string) {
+	if result.Properties == nil {
+		result.Properties = map[string]any{}
+	}
+	inputs, _ := result.Properties["inputs"].([]string)
+	for _, p := range inputs {
+		if p == path {
+			return
+		}
+	}
+	result.Properties["inputs"] = append(inputs, path)
 }
@@ -1,6 +1,9 @@
 package internal
 
 import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
@@ -141,3 +144,209 @@ func TestConvertToSARIF_Options(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertFilesToSARIF_MergesInputs(t *testing.T) {
+	dir := t.TempDir()
+
+	shards := map[string]string{
+		"shard-1.json": `{"Action":"fail","Package":"example.com/foo","Test":"TestBar","Output":"failed"}` + "\n",
+		"shard-2.json": `{"Action":"fail","Package":"example.com/foo","Test":"TestBar","Output":"failed"}` + "\n" +
+			`{"Action":"fail","Package":"example.com/foo","Test":"TestBaz","Output":"failed"}` + "\n",
+	}
+	var inputs []string
+	for _, name := range []string{"shard-1.json", "shard-2.json"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(shards[name]), 0o600); err != nil {
+			t.Fatalf("Failed to write input file: %v", err)
+		}
+		inputs = append(inputs, path)
+	}
+
+	outputPath := filepath.Join(dir, testutil.OutputSARIF)
+	if err := ConvertFilesToSARIF(inputs, outputPath, DefaultConvertOptions()); err != nil {
+		t.Fatalf("ConvertFilesToSARIF returned an error: %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+
+	var doc struct {
+		Runs []struct {
+			Results []struct {
+				Properties struct {
+					Inputs []string `json:"inputs"`
+				} `json:"properties"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid SARIF output: %v", err)
+	}
+
+	results := doc.Runs[0].Results
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if got := results[0].Properties.Inputs; len(got) != 2 {
+		t.Errorf("TestBar inputs = %v, want both shards", got)
+	}
+	if got := results[1].Properties.Inputs; len(got) != 1 || got[0] != inputs[1] {
+		t.Errorf("TestBaz inputs = %v, want [%s]", got, inputs[1])
+	}
+}
+
+func TestConvertToSARIF_GzipOutput(t *testing.T) {
+	dir := t.TempDir()
+
+	inputPath := filepath.Join(dir, testutil.InputJSON)
+	inputContent := `{"Action":"fail","Package":"example.com/foo","Test":"TestBar","Output":"failed"}` + "\n"
+	if err := os.WriteFile(inputPath, []byte(inputContent), 0o600); err != nil {
+		t.Fatalf("Failed to write input file: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		output   string
+		compress bool
+	}{
+		{name: "by extension", output: "output.sarif.gz"},
+		{name: "by option", output: testutil.OutputSARIF, compress: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), tt.output)
+			opts := DefaultConvertOptions()
+			opts.Compress = tt.compress
+			if err := ConvertToSARIF(inputPath, outputPath, opts); err != nil {
+				t.Fatalf("ConvertToSARIF returned an error: %v", err)
+			}
+
+			f, err := os.Open(outputPath)
+			if err != nil {
+				t.Fatalf("Failed to open output file: %v", err)
+			}
+			defer func() { _ = f.Close() }()
+
+			zr, err := gzip.NewReader(f)
+			if err != nil {
+				t.Fatalf("output is not gzip-compressed: %v", err)
+			}
+			data, err := io.ReadAll(zr)
+			if err != nil {
+				t.Fatalf("Failed to decompress output: %v", err)
+			}
+			if !json.Valid(data) {
+				t.Errorf("decompressed output is not valid JSON: %s", data)
+			}
+		})
+	}
+}
+
+func TestConvertToSARIF_RawTest2JSON(t *testing.T) {
+	inputJSON := `{"Action":"fail","Test":"TestExample","Output":"failed"}` + "\n" +
+		`{"Action":"fail"}` + "\n"
+
+	opts := DefaultConvertOptions()
+	opts.Package = "example.com/foo"
+	data, err := testConvertHelper(t, inputJSON, opts)
+	if err != nil {
+		t.Fatalf("ConvertToSARIF returned an error: %v", err)
+	}
+
+	var doc struct {
+		Runs []struct {
+			Results []struct {
+				LogicalLocations []struct {
+					FullyQualifiedName string `json:"fullyQualifiedName"`
+				} `json:"logicalLocations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid SARIF output: %v", err)
+	}
+
+	results := doc.Runs[0].Results
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want test and package failures", len(results))
+	}
+	want := []string{"example.com/foo.TestExample", "example.com/foo"}
+	for i, res := range results {
+		if got := res.LogicalLocations[0].FullyQualifiedName; got != want[i] {
+			t.Errorf("results[%d] location = %q, want %q", i, got, want[i])
+		}
+	}
+}
+
+func TestConvertToSARIF_Attributes(t *testing.T) {
+	inputJSON := `{"Action":"attr","Package":"example.com/foo","Test":"TestBar","Key":"owner","Value":"team-a"}` + "\n" +
+		`{"Action":"attr","Package":"example.com/foo","Test":"TestOther","Key":"owner","Value":"team-b"}` + "\n" +
+		`{"Action":"fail","Package":"example.com/foo","Test":"TestBar"}` + "\n"
+
+	opts := DefaultConvertOptions()
+	opts.Attributes.OwnersKey = "owner"
+	data, err := testConvertHelper(t, inputJSON, opts)
+	if err != nil {
+		t.Fatalf("ConvertToSARIF returned an error: %v", err)
+	}
+
+	var doc struct {
+		Runs []struct {
+			Results []struct {
+				Properties struct {
+					Attributes map[string]string `json:"attributes"`
+					Owners     []string          `json:"owners"`
+				} `json:"properties"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid SARIF output: %v", err)
+	}
+
+	props := doc.Runs[0].Results[0].Properties
+	if props.Attributes["owner"] != "team-a" {
+		t.Errorf("attributes = %v, want owner team-a", props.Attributes)
+	}
+	if len(props.Owners) != 1 || props.Owners[0] != "team-a" {
+		t.Errorf("owners = %v, want [team-a]", props.Owners)
+	}
+}
+
+func TestConvertToSARIF_Artifacts(t *testing.T) {
+	artifacts := t.TempDir()
+	if err := os.WriteFile(filepath.Join(artifacts, "diff.txt"), []byte("diff"), 0o600); err != nil {
+		t.Fatalf("Failed to write artifact: %v", err)
+	}
+	path, _ := json.Marshal(artifacts)
+
+	inputJSON := `{"Action":"artifacts","Package":"example.com/foo","Test":"TestBar","Path":` + string(path) + `}` + "\n" +
+		`{"Action":"fail","Package":"example.com/foo","Test":"TestBar"}` + "\n"
+
+	data, err := testConvertHelper(t, inputJSON, DefaultConvertOptions())
+	if err != nil {
+		t.Fatalf("ConvertToSARIF returned an error: %v", err)
+	}
+
+	var doc struct {
+		Runs []struct {
+			Results []struct {
+				Attachments []json.RawMessage `json:"attachments"`
+			} `json:"results"`
+			Artifacts []json.RawMessage `json:"artifacts"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid SARIF output: %v", err)
+	}
+
+	if got := len(doc.Runs[0].Results[0].Attachments); got != 1 {
+		t.Errorf("got %d attachments, want 1", got)
+	}
+	if got := len(doc.Runs[0].Artifacts); got != 1 {
+		t.Errorf("got %d run artifacts, want 1", got)
+	}
+}
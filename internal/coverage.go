@@ -0,0 +1,333 @@
+package internal
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/compress"
+	"github.com/ivuorinen/go-test-sarif-action/internal/coverage"
+	"github.com/ivuorinen/go-test-sarif-action/internal/diff"
+	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
+	"github.com/ivuorinen/go-test-sarif-action/internal/source"
+)
+
+// coverageRule is reported for files and packages whose statement
+// coverage is below the configured threshold.
+var coverageRule = sarif.Rule{
+	ID:          "go-coverage-low",
+	Description: "low test coverage",
+}
+
+// uncoveredChangeRule is reported for changed lines that no test ran.
+var uncoveredChangeRule = sarif.Rule{
+	ID:          "go-coverage-uncovered-change",
+	Description: "changed lines not covered by tests",
+}
+
+// maxUncoveredRegions limits the uncovered blocks attached to a result as
+// related locations.
+const maxUncoveredRegions = 100
+
+// CoverageOptions selects the files and packages reported for low
+// statement coverage. The zero value reports none.
+type CoverageOptions struct {
+	// FileThreshold is the percentage of statements each file must cover.
+	// Zero disables it.
+	FileThreshold float64
+	// PackageThreshold is the percentage of statements each package must
+	// cover. The coverage of a package is taken from the profiles when
+	// they list it, and otherwise from the coverage line go test -cover
+	// prints. Zero disables it.
+	PackageThreshold float64
+	// Diff names a unified diff, such as git diff output, whose added
+	// lines are reported when they are executable but not covered.
+	Diff string
+	// Summary names a file to write the coverage printed for each package
+	// to as JSON.
+	Summary string
+}
+
+// coverageInputs are the coverage profiles read from the inputs.
+type coverageInputs struct {
+	profiles []*coverage.Profile
+	// paths maps the files of the profiles to the inputs listing them.
+	paths map[string][]string
+}
+
+// coverageProfiles returns the coverage profiles of the inputs.
+func coverageProfiles(inputs []input) coverageInputs {
+	c := coverageInputs{paths: make(map[string][]string)}
+	for _, in := range inputs {
+		if in.Format != formatCoverProfile {
+			continue
+		}
+		c.profiles = append(c.profiles, in.Coverage)
+		for _, blk := range in.Coverage.Blocks {
+			if paths := c.paths[blk.File]; !slices.Contains(paths, in.Path) {
+				c.paths[blk.File] = append(paths, in.Path)
+			}
+		}
+	}
+	return c
+}
+
+// inputsOf returns the inputs listing any file whose name satisfies match.
+func (c *coverageInputs) inputsOf(match func(file string) bool) []string {
+	var out []string
+	for file, paths := range c.paths {
+		if !match(file) {
+			continue
+		}
+		for _, p := range paths {
+			if !slices.Contains(out, p) {
+				out = append(out, p)
+			}
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+// addCoverageResults records the coverage go test printed for each
+// package in the properties of the run, and reports the files and
+// packages whose coverage is below the thresholds of opts. Packages are
+// measured by the merged profiles when they list them, and otherwise by
+// the printed coverage.
+func addCoverageResults(b *reportBuilder, inputs []input, opts CoverageOptions, resolver *source.Resolver) {
+	printed := packageCoverages(inputs)
+	recordPackageCoverage(b.report, printed)
+
+	c := coverageProfiles(inputs)
+	profile := coverage.Merge(c.profiles...)
+
+	if opts.PackageThreshold > 0 {
+		profiled := make(map[string]bool)
+		for _, s := range profile.Packages() {
+			profiled[s.Name] = true
+			if s.Percent() >= opts.PackageThreshold {
+				continue
+			}
+			b.addRule(coverageRule)
+			result := coverageResult(s, opts.PackageThreshold)
+			result.Location = &sarif.LogicalLocation{Module: s.Name}
+			for _, p := range c.inputsOf(func(file string) bool { return path.Dir(file) == s.Name }) {
+				b.add(result, p)
+			}
+		}
+		for _, pc := range printed {
+			if profiled[pc.ImportPath] || pc.Coverage >= opts.PackageThreshold {
+				continue
+			}
+			b.addRule(coverageRule)
+			result := printedCoverageResult(pc, opts.PackageThreshold)
+			for _, p := range pc.Inputs {
+				b.add(result, p)
+			}
+		}
+	}
+
+	if opts.FileThreshold > 0 {
+		for _, s := range profile.Files() {
+			if s.Percent() >= opts.FileThreshold {
+				continue
+			}
+			b.addRule(coverageRule)
+			result := coverageResult(s, opts.FileThreshold)
+			result.Location = &sarif.LogicalLocation{Module: path.Dir(s.Name)}
+			uri := coverageFileURI(s.Name, resolver)
+			result.PhysicalLocation = &sarif.PhysicalLocation{URI: uri}
+			for _, blk := range s.Uncovered[:min(len(s.Uncovered), maxUncoveredRegions)] {
+				msg := fmt.Sprintf("%d statements not covered", blk.NumStmt)
+				if blk.NumStmt == 1 {
+					msg = "1 statement not covered"
+				}
+				result.RelatedLocations = append(result.RelatedLocations, sarif.RelatedLocation{
+					Message: msg,
+					Location: sarif.PhysicalLocation{
+						URI:         uri,
+						StartLine:   blk.StartLine,
+						StartColumn: blk.StartCol,
+						EndLine:     blk.EndLine,
+						EndColumn:   blk.EndCol,
+					},
+				})
+			}
+			result.Properties["uncoveredBlocks"] = len(s.Uncovered)
+			for _, p := range c.inputsOf(func(file string) bool { return file == s.Name }) {
+				b.add(result, p)
+			}
+		}
+	}
+}
+
+// coverageResult reports the coverage of a file or package below
+// threshold.
+func coverageResult(s coverage.Stats, threshold float64) sarif.Result {
+	return sarif.Result{
+		RuleID: coverageRule.ID,
+		Level:  "warning",
+		Message: fmt.Sprintf("%s: %.1f%% of statements covered (%d/%d), below the %g%% threshold",
+			s.Name, s.Percent(), s.Covered, s.Total, threshold),
+		Properties: map[string]any{
+			"covered":   s.Covered,
+			"total":     s.Total,
+			"coverage":  s.Percent(),
+			"threshold": threshold,
+		},
+	}
+}
+
+// coverageFileURI returns the URI of a file named in a coverage profile,
+// usually by the import path of its package. Files of packages outside
+// the module at the source root keep their recorded name.
+func coverageFileURI(name string, resolver *source.Resolver) string {
+	if filepath.IsAbs(name) {
+		return resolver.URI(name)
+	}
+	if dir, ok := resolver.PackageDir(path.Dir(name)); ok {
+		return resolver.URI(filepath.Join(dir, path.Base(name)))
+	}
+	return name
+}
+
+// readDiff reads the files of a unified diff.
+func readDiff(file string) ([]diff.File, error) {
+	rc, err := compress.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rc.Close() }()
+
+	files, err := diff.ParseFiles(rc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return files, nil
+}
+
+// addDiffCoverageResults reports the lines added by the diff of opts that
+// are executable but not covered by the profiles of the inputs. Adjacent
+// lines are reported as one region. It is an error for no input to be a
+// coverage profile.
+func addDiffCoverageResults(report *sarif.Report, inputs []input, opts ConvertOptions) error {
+	if opts.Coverage.Diff == "" {
+		return nil
+	}
+	c := coverageProfiles(inputs)
+	if len(c.profiles) == 0 {
+		return fmt.Errorf("%s: diff coverage needs a coverage profile among the inputs", opts.Coverage.Diff)
+	}
+	files, err := readDiff(opts.Coverage.Diff)
+	if err != nil {
+		return err
+	}
+	profile := coverage.Merge(c.profiles...)
+	resolver := source.NewResolver(opts.SourceRoot)
+
+	// Diffs name files relative to the repository, profiles by import
+	// path; match them by URI.
+	byURI := make(map[string]coverage.Stats)
+	for _, s := range profile.Files() {
+		byURI[coverageFileURI(s.Name, resolver)] = s
+	}
+	blocks := make(map[string][]coverage.Block)
+	for _, blk := range profile.Blocks {
+		blocks[blk.File] = append(blocks[blk.File], blk)
+	}
+
+	b := &reportBuilder{report: report, seen: make(map[resultKey]int)}
+	for _, f := range files {
+		if f.NewName == "" {
+			continue
+		}
+		uri := resolver.URI(filepath.FromSlash(f.NewName))
+		s, ok := byURI[uri]
+		if !ok {
+			continue
+		}
+		lines := lineCoverage(blocks[s.Name])
+		for _, r := range uncoveredRegions(f.AddedLines(), lines) {
+			b.addRule(uncoveredChangeRule)
+			result := uncoveredChangeResult(uri, path.Dir(s.Name), r)
+			for _, p := range c.inputsOf(func(file string) bool { return file == s.Name }) {
+				b.add(result, p)
+			}
+		}
+	}
+	return nil
+}
+
+// lineCoverage returns whether each executable line of a file's blocks
+// ran. Lines shared by several blocks are covered if any of them ran.
+func lineCoverage(blocks []coverage.Block) map[int]bool {
+	lines := make(map[int]bool)
+	for _, blk := range blocks {
+		if blk.NumStmt == 0 {
+			continue
+		}
+		for l := blk.StartLine; l <= blk.EndLine; l++ {
+			lines[l] = lines[l] || blk.Covered()
+		}
+	}
+	return lines
+}
+
+// lineRegion is a range of lines and the number of executable lines in it.
+type lineRegion struct {
+	start, end, lines int
+}
+
+// uncoveredRegions groups the added lines that are executable but not
+// covered into regions of adjacent lines. Blank and comment lines
+// neither start nor end a region, but do not break one either.
+func uncoveredRegions(added []diff.Line, covered map[int]bool) []lineRegion {
+	var out []lineRegion
+	var cur *lineRegion
+	prev := 0
+	for _, l := range added {
+		if l.Number != prev+1 {
+			cur = nil
+		}
+		prev = l.Number
+		text := strings.TrimSpace(l.Text)
+		ran, executable := covered[l.Number]
+		switch {
+		case !executable || text == "" || strings.HasPrefix(text, "//"):
+		case ran:
+			cur = nil
+		case cur == nil:
+			out = append(out, lineRegion{start: l.Number, end: l.Number, lines: 1})
+			cur = &out[len(out)-1]
+		default:
+			cur.end = l.Number
+			cur.lines++
+		}
+	}
+	return out
+}
+
+// uncoveredChangeResult reports a region of changed lines of the file at
+// uri, in package pkg, that no test ran.
+func uncoveredChangeResult(uri, pkg string, r lineRegion) sarif.Result {
+	lines := fmt.Sprintf("line %d", r.start)
+	if r.end > r.start {
+		lines = fmt.Sprintf("lines %d-%d", r.start, r.end)
+	}
+	return sarif.Result{
+		RuleID:   uncoveredChangeRule.ID,
+		Level:    "note",
+		Message:  fmt.Sprintf("%s: changed %s not covered by tests", uri, lines),
+		Location: &sarif.LogicalLocation{Module: pkg},
+		PhysicalLocation: &sarif.PhysicalLocation{
+			URI:       uri,
+			StartLine: r.start,
+			EndLine:   r.end,
+		},
+		Properties: map[string]any{
+			"uncoveredLines": r.lines,
+		},
+	}
+}
@@ -0,0 +1,211 @@
+// Package coverage provides parsing utilities for the coverage profiles
+// written by go test -coverprofile.
+package coverage
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Profile is a parsed coverage profile.
+type Profile struct {
+	// Mode is the coverage mode: set, count or atomic.
+	Mode string
+	// Blocks are the code blocks, sorted by file and position. Blocks
+	// listed more than once, as with -coverpkg, are merged.
+	Blocks []Block
+}
+
+// Block is a block of statements with its execution count.
+type Block struct {
+	// File is the file name as recorded, usually the package import path
+	// followed by the file name.
+	File string
+	// StartLine, StartCol, EndLine and EndCol delimit the block; columns
+	// are 1-based byte offsets.
+	StartLine, StartCol, EndLine, EndCol int
+	// NumStmt is the number of statements in the block.
+	NumStmt int
+	// Count is the number of times the block ran, or 1 for a block that
+	// ran in set mode.
+	Count int
+}
+
+// Covered reports whether the block ran.
+func (b Block) Covered() bool {
+	return b.Count > 0
+}
+
+// blockRe matches a block line, "file.go:12.34,15.2 3 1".
+var blockRe = regexp.MustCompile(`^(.+):(\d+)\.(\d+),(\d+)\.(\d+) (\d+) (\d+)$`)
+
+// Parse reads a coverage profile. Profiles concatenated into one file,
+// each starting with a mode line, are merged.
+func Parse(r io.Reader) (*Profile, error) {
+	p := &Profile{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if mode, ok := strings.CutPrefix(line, "mode: "); ok {
+			if p.Mode != "" && p.Mode != mode {
+				return nil, fmt.Errorf("line %d: mode %s conflicts with %s", lineNum, mode, p.Mode)
+			}
+			p.Mode = mode
+			continue
+		}
+		if p.Mode == "" {
+			return nil, fmt.Errorf("line %d: missing mode line", lineNum)
+		}
+		m := blockRe.FindStringSubmatch(line)
+		if m == nil {
+			return nil, fmt.Errorf("line %d: invalid block %q", lineNum, line)
+		}
+		b := Block{File: m[1]}
+		for i, field := range []*int{&b.StartLine, &b.StartCol, &b.EndLine, &b.EndCol, &b.NumStmt, &b.Count} {
+			n, err := strconv.Atoi(m[i+2])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			*field = n
+		}
+		p.Blocks = append(p.Blocks, b)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if p.Mode == "" {
+		return nil, fmt.Errorf("missing mode line")
+	}
+	p.normalize()
+	return p, nil
+}
+
+// Merge combines profiles, as of packages tested separately, into one.
+// Counts of the same block are added, except in set mode.
+func Merge(profiles ...*Profile) *Profile {
+	out := &Profile{}
+	for _, p := range profiles {
+		if out.Mode == "" {
+			out.Mode = p.Mode
+		}
+		out.Blocks = append(out.Blocks, p.Blocks...)
+	}
+	out.normalize()
+	return out
+}
+
+// normalize sorts the blocks and merges duplicates.
+func (p *Profile) normalize() {
+	slices.SortStableFunc(p.Blocks, compareBlocks)
+	var merged []Block
+	for _, b := range p.Blocks {
+		if n := len(merged); n > 0 && compareBlocks(merged[n-1], b) == 0 {
+			last := &merged[n-1]
+			if p.Mode == "set" {
+				last.Count = max(last.Count, b.Count)
+			} else {
+				last.Count += b.Count
+			}
+			continue
+		}
+		merged = append(merged, b)
+	}
+	p.Blocks = merged
+}
+
+// compareBlocks orders blocks by file and position.
+func compareBlocks(a, b Block) int {
+	return cmp.Or(
+		cmp.Compare(a.File, b.File),
+		cmp.Compare(a.StartLine, b.StartLine),
+		cmp.Compare(a.StartCol, b.StartCol),
+		cmp.Compare(a.EndLine, b.EndLine),
+		cmp.Compare(a.EndCol, b.EndCol),
+	)
+}
+
+// Stats counts the statements of a file or package.
+type Stats struct {
+	// Name is the file or package import path.
+	Name string
+	// Covered is the number of statements that ran.
+	Covered int
+	// Total is the number of statements.
+	Total int
+	// Uncovered are the blocks that did not run, for files.
+	Uncovered []Block
+}
+
+// Percent returns the percentage of statements covered, or 100 when
+// there are none.
+func (s Stats) Percent() float64 {
+	if s.Total == 0 {
+		return 100
+	}
+	return float64(s.Covered) / float64(s.Total) * 100
+}
+
+// add counts b.
+func (s *Stats) add(b Block) {
+	s.Total += b.NumStmt
+	if b.Covered() {
+		s.Covered += b.NumStmt
+	}
+}
+
+// Files returns the statement counts of each file, sorted by name.
+func (p *Profile) Files() []Stats {
+	var out []Stats
+	for _, b := range p.Blocks {
+		if len(out) == 0 || out[len(out)-1].Name != b.File {
+			out = append(out, Stats{Name: b.File})
+		}
+		s := &out[len(out)-1]
+		s.add(b)
+		if !b.Covered() && b.NumStmt > 0 {
+			s.Uncovered = append(s.Uncovered, b)
+		}
+	}
+	return out
+}
+
+// Packages returns the statement counts of each package, named after the
+// directory of its files and sorted by name.
+func (p *Profile) Packages() []Stats {
+	index := make(map[string]int)
+	var out []Stats
+	for _, b := range p.Blocks {
+		pkg := path.Dir(b.File)
+		i, ok := index[pkg]
+		if !ok {
+			i = len(out)
+			index[pkg] = i
+			out = append(out, Stats{Name: pkg})
+		}
+		out[i].add(b)
+	}
+	slices.SortFunc(out, func(a, b Stats) int { return cmp.Compare(a.Name, b.Name) })
+	return out
+}
+
+// Total returns the statement counts of the whole profile.
+func (p *Profile) Total() Stats {
+	var s Stats
+	for _, b := range p.Blocks {
+		s.add(b)
+	}
+	return s
+}
@@ -0,0 +1,107 @@
+package coverage
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sample = `mode: set
+example.com/app/foo/a.go:3.14,5.2 1 1
+example.com/app/foo/a.go:7.14,9.16 2 0
+example.com/app/foo/a.go:9.16,11.3 1 0
+example.com/app/foo/b.go:3.14,5.2 3 1
+example.com/app/bar/c.go:3.14,5.2 4 0
+`
+
+func TestParse(t *testing.T) {
+	p, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if p.Mode != "set" {
+		t.Errorf("mode = %q, want set", p.Mode)
+	}
+	if len(p.Blocks) != 5 {
+		t.Fatalf("got %d blocks, want 5", len(p.Blocks))
+	}
+	want := Block{File: "example.com/app/bar/c.go", StartLine: 3, StartCol: 14, EndLine: 5, EndCol: 2, NumStmt: 4}
+	if p.Blocks[0] != want {
+		t.Errorf("first block = %+v, want %+v", p.Blocks[0], want)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := map[string]string{
+		"empty":         "",
+		"no mode":       "a.go:1.1,2.2 1 1\n",
+		"invalid block": "mode: set\na.go:1.1 1 1\n",
+		"mixed modes":   "mode: set\nmode: count\n",
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(input)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	parse := func(s string) *Profile {
+		p, err := Parse(strings.NewReader(s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	set := Merge(
+		parse("mode: set\na.go:1.1,2.2 1 1\nb.go:1.1,2.2 1 0\n"),
+		parse("mode: set\na.go:1.1,2.2 1 1\nb.go:1.1,2.2 1 0\n"),
+	)
+	if got := []int{set.Blocks[0].Count, set.Blocks[1].Count}; !reflect.DeepEqual(got, []int{1, 0}) {
+		t.Errorf("set counts = %v, want [1 0]", got)
+	}
+	count := Merge(
+		parse("mode: count\na.go:1.1,2.2 1 2\n"),
+		parse("mode: count\na.go:1.1,2.2 1 3\n"),
+	)
+	if len(count.Blocks) != 1 || count.Blocks[0].Count != 5 {
+		t.Errorf("count blocks = %+v, want one block with count 5", count.Blocks)
+	}
+}
+
+func TestStats(t *testing.T) {
+	p, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := p.Files()
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name)
+	}
+	wantNames := []string{"example.com/app/bar/c.go", "example.com/app/foo/a.go", "example.com/app/foo/b.go"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Fatalf("files = %v, want %v", names, wantNames)
+	}
+	a := files[1]
+	if a.Covered != 1 || a.Total != 4 || len(a.Uncovered) != 2 {
+		t.Errorf("a.go = %d/%d with %d uncovered blocks, want 1/4 with 2", a.Covered, a.Total, len(a.Uncovered))
+	}
+	if got := a.Percent(); got != 25 {
+		t.Errorf("a.go percent = %v, want 25", got)
+	}
+
+	pkgs := p.Packages()
+	if len(pkgs) != 2 || pkgs[1].Name != "example.com/app/foo" || pkgs[1].Covered != 4 || pkgs[1].Total != 7 {
+		t.Errorf("packages = %+v", pkgs)
+	}
+	if total := p.Total(); total.Covered != 4 || total.Total != 11 {
+		t.Errorf("total = %d/%d, want 4/11", total.Covered, total.Total)
+	}
+	if got := (Stats{}).Percent(); got != 100 {
+		t.Errorf("empty percent = %v, want 100", got)
+	}
+}
@@ -0,0 +1,181 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/coverage"
+	"github.com/ivuorinen/go-test-sarif-action/internal/diff"
+	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
+)
+
+// parseProfile parses a coverage profile, failing the test on error.
+func parseProfile(t *testing.T, data string) *coverage.Profile {
+	t.Helper()
+	p, err := coverage.Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to parse profile: %v", err)
+	}
+	return p
+}
+
+func TestAddCoverageResults(t *testing.T) {
+	resolver := writeTestModule(t, map[string]string{
+		"foo/x.go": "package foo\n",
+		"foo/y.go": "package foo\n",
+	})
+	unit := input{Path: "unit.out", Format: formatCoverProfile, Coverage: parseProfile(t, "mode: set\n"+
+		"example.com/app/foo/x.go:3.14,5.2 1 1\n"+
+		"example.com/app/foo/x.go:7.14,9.16 2 0\n"+
+		"example.com/app/foo/x.go:9.16,11.3 1 0\n"+
+		"example.com/app/foo/y.go:3.14,5.2 3 1\n")}
+	integration := input{Path: "integration.out", Format: formatCoverProfile, Coverage: parseProfile(t, "mode: set\n"+
+		"example.com/app/foo/x.go:9.16,11.3 1 1\n"+
+		"example.com/other/z.go:1.1,2.2 4 0\n")}
+
+	opts := ConvertOptions{
+		SourceRoot: resolver.Root(),
+		Coverage:   CoverageOptions{FileThreshold: 60, PackageThreshold: 80},
+	}
+	results := buildReport([]input{unit, integration}, opts).Results
+
+	var got []string
+	for _, r := range results {
+		if r.RuleID != coverageRule.ID || r.Level != "warning" {
+			t.Errorf("unexpected result %+v", r)
+		}
+		got = append(got, r.Message)
+	}
+	want := []string{
+		"example.com/app/foo: 71.4% of statements covered (5/7), below the 80% threshold",
+		"example.com/other: 0.0% of statements covered (0/4), below the 80% threshold",
+		"example.com/app/foo/x.go: 50.0% of statements covered (2/4), below the 60% threshold",
+		"example.com/other/z.go: 0.0% of statements covered (0/4), below the 60% threshold",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("messages = %q, want %q", got, want)
+	}
+
+	pkg := results[0]
+	if pkg.PhysicalLocation != nil || pkg.Location.Module != "example.com/app/foo" {
+		t.Errorf("package location = %+v, %+v", pkg.Location, pkg.PhysicalLocation)
+	}
+	if !reflect.DeepEqual(pkg.Properties["inputs"], []string{"integration.out", "unit.out"}) {
+		t.Errorf("package inputs = %v", pkg.Properties["inputs"])
+	}
+
+	file := results[2]
+	if file.PhysicalLocation == nil || file.PhysicalLocation.URI != "foo/x.go" || file.PhysicalLocation.StartLine != 0 {
+		t.Errorf("file location = %+v, want the whole of foo/x.go", file.PhysicalLocation)
+	}
+	if file.Properties["covered"] != 2 || file.Properties["total"] != 4 || file.Properties["coverage"] != 50.0 ||
+		file.Properties["threshold"] != 60.0 || file.Properties["uncoveredBlocks"] != 1 {
+		t.Errorf("file properties = %v", file.Properties)
+	}
+	if len(file.RelatedLocations) != 1 {
+		t.Fatalf("related locations = %+v, want one", file.RelatedLocations)
+	}
+	rel := file.RelatedLocations[0]
+	if rel.Message != "2 statements not covered" || rel.Location.URI != "foo/x.go" ||
+		rel.Location.StartLine != 7 || rel.Location.StartColumn != 14 || rel.Location.EndLine != 9 || rel.Location.EndColumn != 16 {
+		t.Errorf("related location = %+v", rel)
+	}
+
+	if uri := results[3].PhysicalLocation.URI; uri != "example.com/other/z.go" {
+		t.Errorf("uri = %q, want the recorded name of a file outside the module", uri)
+	}
+}
+
+func TestAddCoverageResults_Disabled(t *testing.T) {
+	in := input{Path: "cover.out", Format: formatCoverProfile, Coverage: parseProfile(t, "mode: set\nexample.com/app/foo/x.go:1.1,2.2 1 0\n")}
+	if results := buildReport([]input{in}, ConvertOptions{}).Results; len(results) != 0 {
+		t.Errorf("results = %+v, want none", results)
+	}
+}
+
+func TestAddDiffCoverageResults(t *testing.T) {
+	resolver := writeTestModule(t, map[string]string{"foo/x.go": "package foo\n"})
+	profile := input{Path: "cover.out", Format: formatCoverProfile, Coverage: parseProfile(t, "mode: set\n"+
+		"example.com/app/foo/x.go:3.14,6.2 2 1\n"+
+		"example.com/app/foo/x.go:8.14,14.2 4 0\n")}
+	patch := "diff --git a/foo/x.go b/foo/x.go\n" +
+		"--- a/foo/x.go\n" +
+		"+++ b/foo/x.go\n" +
+		"@@ -4,0 +4,1 @@\n" +
+		"+\ta()\n" +
+		"@@ -9,2 +9,6 @@\n" +
+		" \tb()\n" +
+		"+\tc()\n" +
+		"+\n" +
+		"+\td()\n" +
+		" \te()\n" +
+		"+\tf()\n" +
+		"diff --git a/bar/y.go b/bar/y.go\n" +
+		"--- a/bar/y.go\n" +
+		"+++ b/bar/y.go\n" +
+		"@@ -1,0 +1,1 @@\n" +
+		"+var y = 1\n"
+	diffFile := filepath.Join(t.TempDir(), "pr.diff")
+	if err := os.WriteFile(diffFile, []byte(patch), 0o600); err != nil {
+		t.Fatalf("failed to write diff: %v", err)
+	}
+
+	report := &sarif.Report{}
+	opts := ConvertOptions{SourceRoot: resolver.Root(), Coverage: CoverageOptions{Diff: diffFile}}
+	if err := addDiffCoverageResults(report, []input{profile}, opts); err != nil {
+		t.Fatalf("addDiffCoverageResults returned error: %v", err)
+	}
+
+	var got []string
+	for _, r := range report.Results {
+		if r.RuleID != uncoveredChangeRule.ID || r.Level != "note" || r.Location.Module != "example.com/app/foo" {
+			t.Errorf("unexpected result %+v", r)
+		}
+		got = append(got, r.Message)
+	}
+	want := []string{
+		"foo/x.go: changed lines 10-12 not covered by tests",
+		"foo/x.go: changed line 14 not covered by tests",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("messages = %q, want %q", got, want)
+	}
+	first := report.Results[0]
+	if loc := first.PhysicalLocation; loc.URI != "foo/x.go" || loc.StartLine != 10 || loc.EndLine != 12 {
+		t.Errorf("location = %+v, want foo/x.go:10-12", loc)
+	}
+	if first.Properties["uncoveredLines"] != 2 {
+		t.Errorf("properties = %v", first.Properties)
+	}
+
+	opts.Coverage.Diff = filepath.Join(t.TempDir(), "missing.diff")
+	if err := addDiffCoverageResults(&sarif.Report{}, []input{profile}, opts); err == nil {
+		t.Error("expected error for a missing diff")
+	}
+
+	opts.Coverage.Diff = diffFile
+	tests := input{Path: "test.json", Events: timedRun(testPkg, "TestA", 0.1)}
+	err := addDiffCoverageResults(&sarif.Report{}, []input{tests}, opts)
+	if err == nil || !strings.Contains(err.Error(), "coverage profile") {
+		t.Errorf("error = %v, want one asking for a coverage profile", err)
+	}
+}
+
+func TestUncoveredRegions(t *testing.T) {
+	covered := map[int]bool{1: false, 2: false, 3: false, 4: true, 5: false, 7: false}
+	added := []diff.Line{
+		{Number: 1, Text: "// comment"},
+		{Number: 2, Text: "a()"},
+		{Number: 3, Text: "b()"},
+		{Number: 4, Text: "c()"},
+		{Number: 5, Text: "d()"},
+		{Number: 7, Text: "e()"},
+	}
+	want := []lineRegion{{2, 3, 2}, {5, 5, 1}, {7, 7, 1}}
+	if got := uncoveredRegions(added, covered); !reflect.DeepEqual(got, want) {
+		t.Errorf("uncoveredRegions() = %+v, want %+v", got, want)
+	}
+}
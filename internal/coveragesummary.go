@@ -0,0 +1,118 @@
+package internal
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
+)
+
+// coveragePercentRe matches the coverage line printed by go test -cover,
+// "coverage: 73.2% of statements", alone or at the end of the ok line.
+var coveragePercentRe = regexp.MustCompile(`coverage: (\d+(?:\.\d+)?)% of statements`)
+
+// packageCoverage is the statement coverage go test printed for a package.
+type packageCoverage struct {
+	// ImportPath is the import path of the package.
+	ImportPath string `json:"importPath"`
+	// Coverage is the percentage of statements covered.
+	Coverage float64 `json:"coverage"`
+	// Inputs are the inputs that reported it.
+	Inputs []string `json:"-"`
+}
+
+// packageCoverages returns the coverage printed for each package by the
+// go test inputs, sorted by import path. A package tested by several
+// inputs is given the highest coverage reported, since the runs cannot be
+// combined without a profile.
+func packageCoverages(inputs []input) []packageCoverage {
+	index := make(map[string]int)
+	var out []packageCoverage
+	for _, in := range inputs {
+		for _, e := range in.Events {
+			if e.Action != "output" || e.Test != "" || e.Package == "" {
+				continue
+			}
+			m := coveragePercentRe.FindStringSubmatch(e.Output)
+			if m == nil {
+				continue
+			}
+			pct, err := strconv.ParseFloat(m[1], 64)
+			if err != nil {
+				continue
+			}
+			i, ok := index[e.Package]
+			if !ok {
+				i = len(out)
+				index[e.Package] = i
+				out = append(out, packageCoverage{ImportPath: e.Package, Coverage: pct})
+			}
+			pc := &out[i]
+			pc.Coverage = max(pc.Coverage, pct)
+			if !slices.Contains(pc.Inputs, in.Path) {
+				pc.Inputs = append(pc.Inputs, in.Path)
+			}
+		}
+	}
+	slices.SortFunc(out, func(a, b packageCoverage) int { return cmp.Compare(a.ImportPath, b.ImportPath) })
+	return out
+}
+
+// recordPackageCoverage records the coverage printed for each package in
+// the properties of the run.
+func recordPackageCoverage(report *sarif.Report, pkgs []packageCoverage) {
+	if len(pkgs) == 0 {
+		return
+	}
+	byPkg := make(map[string]float64, len(pkgs))
+	for _, pc := range pkgs {
+		byPkg[pc.ImportPath] = pc.Coverage
+	}
+	if report.Properties == nil {
+		report.Properties = map[string]any{}
+	}
+	report.Properties["coverage"] = byPkg
+}
+
+// printedCoverageResult reports a package whose printed coverage is below
+// threshold.
+func printedCoverageResult(pc packageCoverage, threshold float64) sarif.Result {
+	return sarif.Result{
+		RuleID: coverageRule.ID,
+		Level:  "warning",
+		Message: fmt.Sprintf("%s: %.1f%% of statements covered, below the %g%% threshold",
+			pc.ImportPath, pc.Coverage, threshold),
+		Location: &sarif.LogicalLocation{Module: pc.ImportPath},
+		Properties: map[string]any{
+			"coverage":  pc.Coverage,
+			"threshold": threshold,
+		},
+	}
+}
+
+// writeCoverageSummary writes the coverage printed for each package to
+// the summary file of opts, if any, as JSON.
+func writeCoverageSummary(inputs []input, opts ConvertOptions) error {
+	if opts.Coverage.Summary == "" {
+		return nil
+	}
+	pkgs := packageCoverages(inputs)
+	if pkgs == nil {
+		pkgs = []packageCoverage{}
+	}
+	data, err := json.MarshalIndent(struct {
+		Packages []packageCoverage `json:"packages"`
+	}{pkgs}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(opts.Coverage.Summary, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing coverage summary: %w", err)
+	}
+	return nil
+}
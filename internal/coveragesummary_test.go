@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/testjson"
+)
+
+// coverageOutput returns the package output go test -cover prints for pkg.
+func coverageOutput(pkg, pct string) []testjson.TestEvent {
+	return []testjson.TestEvent{
+		{Action: "output", Package: pkg, Output: "coverage: " + pct + "% of statements\n"},
+		{Action: "output", Package: pkg, Output: "ok  \t" + pkg + "\t0.01s\tcoverage: " + pct + "% of statements\n"},
+		{Action: "pass", Package: pkg, Elapsed: 0.01},
+	}
+}
+
+func TestPrintedCoverage(t *testing.T) {
+	shard1 := input{Path: "shard1.json", Events: append(coverageOutput(testPkg, "42.5"), coverageOutput("example.com/app/db", "90.0")...)}
+	shard2 := input{Path: "shard2.json", Events: append(coverageOutput(testPkg, "55.0"),
+		testjson.TestEvent{Action: "output", Package: "example.com/app/empty", Output: "coverage: [no statements]\n"})}
+
+	report := buildReport([]input{shard1, shard2}, ConvertOptions{Coverage: CoverageOptions{PackageThreshold: 60}})
+
+	want := map[string]float64{testPkg: 55, "example.com/app/db": 90}
+	if got := report.Properties["coverage"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("run coverage = %v, want %v", got, want)
+	}
+	if len(report.Results) != 1 {
+		t.Fatalf("results = %+v, want one", report.Results)
+	}
+	r := report.Results[0]
+	if r.RuleID != coverageRule.ID || r.Message != testPkg+": 55.0% of statements covered, below the 60% threshold" {
+		t.Errorf("result = %s: %s", r.RuleID, r.Message)
+	}
+	if r.Location.Module != testPkg || r.Properties["coverage"] != 55.0 || r.Properties["threshold"] != 60.0 {
+		t.Errorf("result location = %+v, properties = %v", r.Location, r.Properties)
+	}
+	if !reflect.DeepEqual(r.Properties["inputs"], []string{"shard1.json", "shard2.json"}) {
+		t.Errorf("inputs = %v", r.Properties["inputs"])
+	}
+}
+
+func TestPrintedCoverage_None(t *testing.T) {
+	in := input{Path: "test.json", Events: timedRun(testPkg, "TestA", 0.1)}
+	report := buildReport([]input{in}, ConvertOptions{Coverage: CoverageOptions{PackageThreshold: 60}})
+	if report.Properties != nil || len(report.Results) != 0 {
+		t.Errorf("properties = %v, results = %+v, want none", report.Properties, report.Results)
+	}
+}
+
+func TestWriteCoverageSummary(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "coverage.json")
+	in := input{Path: "test.json", Events: coverageOutput(testPkg, "73.2")}
+	if err := writeCoverageSummary([]input{in}, ConvertOptions{Coverage: CoverageOptions{Summary: file}}); err != nil {
+		t.Fatalf("writeCoverageSummary returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("failed to read summary: %v", err)
+	}
+	var doc struct {
+		Packages []packageCoverage `json:"packages"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid summary %s: %v", data, err)
+	}
+	want := []packageCoverage{{ImportPath: testPkg, Coverage: 73.2}}
+	if !reflect.DeepEqual(doc.Packages, want) {
+		t.Errorf("packages = %+v, want %+v", doc.Packages, want)
+	}
+}
+
+func TestPrintedCoverage_ProfileTakesPrecedence(t *testing.T) {
+	test := input{Path: "test.json", Events: coverageOutput(testPkg, "40.0")}
+	profile := input{Path: "cover.out", Format: formatCoverProfile, Coverage: parseProfile(t, "mode: set\n"+
+		"example.com/app/foo/x.go:3.14,5.2 2 1\n"+
+		"example.com/app/foo/x.go:7.14,9.2 3 0\n")}
+
+	report := buildReport([]input{test, profile}, ConvertOptions{Coverage: CoverageOptions{PackageThreshold: 60}})
+	if len(report.Results) != 1 {
+		t.Fatalf("results = %+v, want one", report.Results)
+	}
+	want := testPkg + ": 40.0% of statements covered (2/5), below the 60% threshold"
+	if got := report.Results[0].Message; got != want {
+		t.Errorf("message = %q, want %q", got, want)
+	}
+}
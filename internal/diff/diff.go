@@ -0,0 +1,274 @@
+// Package diff computes line differences, renders them as unified diffs,
+// and parses unified diffs and applies them to text.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind identifies the type of an edit operation.
+type Kind byte
+
+const (
+	// Equal marks a line present in both inputs.
+	Equal Kind = ' '
+	// Delete marks a line present only in the old input.
+	Delete Kind = '-'
+	// Insert marks a line present only in the new input.
+	Insert Kind = '+'
+)
+
+// Op is one line of an edit script.
+type Op struct {
+	// Kind is the type of edit.
+	Kind Kind
+	// Line is the line text, without a trailing newline.
+	Line string
+}
+
+// Lines computes a shortest edit script turning old into new using
+// the linear-space variant of Myers' algorithm, which bisects the inputs
+// at the middle snake of an optimal path so memory stays proportional to
+// the input size.
+func Lines(old, new []string) []Op {
+	size := 2*((len(old)+len(new)+1)/2) + 2
+	s := &lineDiff{
+		old:     old,
+		new:     new,
+		forward: make([]int, size),
+		reverse: make([]int, size),
+	}
+	s.compare(0, len(old), 0, len(new))
+	return s.ops
+}
+
+// lineDiff holds the state of one Lines computation. The frontier
+// buffers are shared by all subproblems, which are never larger than the
+// whole inputs.
+type lineDiff struct {
+	old, new         []string
+	forward, reverse []int
+	ops              []Op
+}
+
+// compare appends the edit script turning old[x0:x1] into new[y0:y1].
+func (s *lineDiff) compare(x0, x1, y0, y1 int) {
+	for x0 < x1 && y0 < y1 && s.old[x0] == s.new[y0] {
+		s.ops = append(s.ops, Op{Kind: Equal, Line: s.old[x0]})
+		x0++
+		y0++
+	}
+	suffix := x1
+	for x1 > x0 && y1 > y0 && s.old[x1-1] == s.new[y1-1] {
+		x1--
+		y1--
+	}
+
+	switch {
+	case x0 == x1:
+		for _, line := range s.new[y0:y1] {
+			s.ops = append(s.ops, Op{Kind: Insert, Line: line})
+		}
+	case y0 == y1:
+		for _, line := range s.old[x0:x1] {
+			s.ops = append(s.ops, Op{Kind: Delete, Line: line})
+		}
+	default:
+		if x, y, ok := s.bisect(x0, x1, y0, y1); ok {
+			s.compare(x0, x, y0, y)
+			s.compare(x, x1, y, y1)
+			break
+		}
+		for _, line := range s.old[x0:x1] {
+			s.ops = append(s.ops, Op{Kind: Delete, Line: line})
+		}
+		for _, line := range s.new[y0:y1] {
+			s.ops = append(s.ops, Op{Kind: Insert, Line: line})
+		}
+	}
+
+	for _, line := range s.old[x1:suffix] {
+		s.ops = append(s.ops, Op{Kind: Equal, Line: line})
+	}
+}
+
+// bisect finds the middle snake of old[x0:x1] and new[y0:y1] by running
+// the search from both ends until the paths overlap, and returns the
+// point at which to split the inputs. Both ranges must be non-empty and
+// differ in their first and last lines. It reports false if the paths
+// do not meet strictly inside the ranges.
+func (s *lineDiff) bisect(x0, x1, y0, y1 int) (int, int, bool) {
+	n, m := x1-x0, y1-y0
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	forward := s.forward[:2*maxD+2]
+	reverse := s.reverse[:2*maxD+2]
+	for i := range forward {
+		forward[i], reverse[i] = -1, -1
+	}
+	forward[offset+1], reverse[offset+1] = 0, 0
+
+	delta := n - m
+	// With an odd delta the paths first overlap on a forward step,
+	// otherwise on a reverse step.
+	odd := delta%2 != 0
+
+	// The diagonal ranges shrink once a path runs off an edge.
+	var fStart, fEnd, rStart, rEnd int
+	for d := 0; d <= maxD; d++ {
+		for k := -d + fStart; k <= d-fEnd; k += 2 {
+			i := offset + k
+			var x int
+			if k == -d || (k != d && forward[i-1] < forward[i+1]) {
+				x = forward[i+1]
+			} else {
+				x = forward[i-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && s.old[x0+x] == s.new[y0+y] {
+				x++
+				y++
+			}
+			forward[i] = x
+			switch {
+			case x > n:
+				fEnd += 2
+			case y > m:
+				fStart += 2
+			case odd:
+				j := offset + delta - k
+				if j >= 0 && j < len(reverse) && reverse[j] != -1 && x >= n-reverse[j] {
+					return splitAt(x0+x, y0+y, x0, x1, y0, y1)
+				}
+			}
+		}
+
+		for k := -d + rStart; k <= d-rEnd; k += 2 {
+			i := offset + k
+			var x int
+			if k == -d || (k != d && reverse[i-1] < reverse[i+1]) {
+				x = reverse[i+1]
+			} else {
+				x = reverse[i-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && s.old[x1-x-1] == s.new[y1-y-1] {
+				x++
+				y++
+			}
+			reverse[i] = x
+			switch {
+			case x > n:
+				rEnd += 2
+			case y > m:
+				rStart += 2
+			case !odd:
+				j := offset + delta - k
+				if j >= 0 && j < len(forward) && forward[j] != -1 {
+					fx := forward[j]
+					if fx >= n-x {
+						return splitAt(x0+fx, y0+fx-(j-offset), x0, x1, y0, y1)
+					}
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// splitAt reports the point x, y as a split of old[x0:x1] and
+// new[y0:y1] if it leaves a smaller problem on both sides.
+func splitAt(x, y, x0, x1, y0, y1 int) (int, int, bool) {
+	inside := (x > x0 || y > y0) && (x < x1 || y < y1)
+	return x, y, inside
+}
+
+// Unified renders the differences between old and new as a unified diff
+// with the given number of context lines. It returns an empty string when
+// the inputs are equal.
+func Unified(oldName, newName string, old, new []string, context int) string {
+	ops := Lines(old, new)
+
+	var b strings.Builder
+	for _, h := range hunks(ops, context) {
+		if b.Len() == 0 {
+			fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+		}
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(h.oldStart, h.oldLines), hunkRange(h.newStart, h.newLines))
+		for _, op := range ops[h.first:h.last] {
+			b.WriteByte(byte(op.Kind))
+			b.WriteString(op.Line)
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// SplitLines splits text into lines, dropping a final line terminator.
+func SplitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// hunk is a range of ops rendered together.
+type hunk struct {
+	first, last        int
+	oldStart, oldLines int
+	newStart, newLines int
+}
+
+// hunks groups changed ops with up to context equal lines around each
+// change, merging groups whose context would overlap.
+func hunks(ops []Op, context int) []hunk {
+	var result []hunk
+	for i := 0; i < len(ops); i++ {
+		if ops[i].Kind == Equal {
+			continue
+		}
+
+		// Extend the group while the next change is close enough.
+		end := i
+		for j := i + 1; j < len(ops) && j <= end+2*context+1; j++ {
+			if ops[j].Kind != Equal {
+				end = j
+			}
+		}
+
+		h := hunk{first: max(i-context, 0), last: min(end+context+1, len(ops))}
+		h.oldStart, h.newStart = 1, 1
+		for _, op := range ops[:h.first] {
+			h.oldStart, h.newStart = advance(op, h.oldStart, h.newStart)
+		}
+		for _, op := range ops[h.first:h.last] {
+			h.oldLines, h.newLines = advance(op, h.oldLines, h.newLines)
+		}
+		result = append(result, h)
+		i = end
+	}
+	return result
+}
+
+// advance counts op against the old and new line counters.
+func advance(op Op, oldN, newN int) (int, int) {
+	switch op.Kind {
+	case Delete:
+		return oldN + 1, newN
+	case Insert:
+		return oldN, newN + 1
+	}
+	return oldN + 1, newN + 1
+}
+
+// hunkRange formats a hunk range in unified diff notation.
+func hunkRange(start, lines int) string {
+	switch lines {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, lines)
+}
@@ -0,0 +1,159 @@
+package diff
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	ops := Lines([]string{"a", "b", "c"}, []string{"a", "x", "c", "d"})
+
+	var got strings.Builder
+	for _, op := range ops {
+		got.WriteByte(byte(op.Kind))
+		got.WriteString(op.Line)
+		got.WriteByte(' ')
+	}
+	if want := " a -b +x  c +d "; got.String() != want {
+		t.Errorf("Lines() = %q, want %q", got.String(), want)
+	}
+}
+
+func TestLines_Empty(t *testing.T) {
+	if ops := Lines(nil, nil); len(ops) != 0 {
+		t.Errorf("Lines(nil, nil) = %v, want no ops", ops)
+	}
+	ops := Lines(nil, []string{"a"})
+	if len(ops) != 1 || ops[0].Kind != Insert {
+		t.Errorf("Lines(nil, [a]) = %v, want one insert", ops)
+	}
+}
+
+func TestLines_Shortest(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	lines := func() []string {
+		l := make([]string, r.IntN(12))
+		for i := range l {
+			l[i] = string(rune('a' + r.IntN(3)))
+		}
+		return l
+	}
+
+	for range 2000 {
+		old, new := lines(), lines()
+		ops := Lines(old, new)
+
+		var gotOld, gotNew []string
+		edits := 0
+		for _, op := range ops {
+			if op.Kind != Insert {
+				gotOld = append(gotOld, op.Line)
+			}
+			if op.Kind != Delete {
+				gotNew = append(gotNew, op.Line)
+			}
+			if op.Kind != Equal {
+				edits++
+			}
+		}
+		if fmt.Sprint(gotOld) != fmt.Sprint(old) || fmt.Sprint(gotNew) != fmt.Sprint(new) {
+			t.Fatalf("Lines(%q, %q) = %v does not turn old into new", old, new, ops)
+		}
+		if want := len(old) + len(new) - 2*lcs(old, new); edits != want {
+			t.Fatalf("Lines(%q, %q) = %v has %d edits, want %d", old, new, ops, edits, want)
+		}
+	}
+}
+
+// lcs returns the length of the longest common subsequence of a and b.
+func lcs(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	for i := range a {
+		cur := make([]int, len(b)+1)
+		for j := range b {
+			if a[i] == b[j] {
+				cur[j+1] = prev[j] + 1
+			} else {
+				cur[j+1] = max(prev[j+1], cur[j])
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func TestLines_LargeInputs(t *testing.T) {
+	const n = 5000
+	old := make([]string, n)
+	new := make([]string, n)
+	for i := range n {
+		old[i] = fmt.Sprintf("old %d", i)
+		new[i] = fmt.Sprintf("new %d", i)
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	ops := Lines(old, new)
+	runtime.ReadMemStats(&after)
+
+	if len(ops) != 2*n {
+		t.Errorf("Lines() returned %d ops, want %d", len(ops), 2*n)
+	}
+	// Keeping a frontier per edit distance would take 2n copies of 2n
+	// ints, over a gigabyte here.
+	if alloc := after.TotalAlloc - before.TotalAlloc; alloc > 16<<20 {
+		t.Errorf("Lines() allocated %d bytes, want at most 16 MiB", alloc)
+	}
+}
+
+func TestUnified(t *testing.T) {
+	old := SplitLines("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n")
+	new := SplitLines("1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n")
+
+	got := Unified("want", "got", old, new, 1)
+	want := `--- want
++++ got
+@@ -2,3 +2,3 @@
+ 2
+-3
++three
+ 4
+@@ -10 +10,2 @@
+ 10
++11
+`
+	if got != want {
+		t.Errorf("Unified() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnified_MergesCloseChanges(t *testing.T) {
+	old := []string{"a", "b", "c", "d", "e"}
+	new := []string{"A", "b", "c", "d", "E"}
+
+	got := Unified("old", "new", old, new, 3)
+	if strings.Count(got, "@@ ") != 1 {
+		t.Errorf("Unified() produced several hunks:\n%s", got)
+	}
+	if !strings.Contains(got, "@@ -1,5 +1,5 @@") {
+		t.Errorf("Unified() hunk header wrong:\n%s", got)
+	}
+}
+
+func TestUnified_Equal(t *testing.T) {
+	if got := Unified("a", "b", []string{"x"}, []string{"x"}, 3); got != "" {
+		t.Errorf("Unified() = %q, want empty", got)
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	if got := SplitLines(""); got != nil {
+		t.Errorf("SplitLines(\"\") = %q, want nil", got)
+	}
+	if got := SplitLines("a\nb\n"); len(got) != 2 || got[1] != "b" {
+		t.Errorf("SplitLines() = %q, want [a b]", got)
+	}
+}
@@ -0,0 +1,94 @@
+package diff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// File is the diff of one file in a multi-file unified diff, such as the
+// output of git diff.
+type File struct {
+	// OldName and NewName are the paths of the file before and after the
+	// change, without the a/ and b/ prefixes of git. A created file has
+	// no OldName and a deleted file no NewName.
+	OldName, NewName string
+	// Hunks are the changes to the file, empty for binary files and
+	// changes of mode or name alone.
+	Hunks []Hunk
+}
+
+// Line is a numbered line of a file.
+type Line struct {
+	// Number is the 1-based line number.
+	Number int
+	// Text is the line, without a trailing newline.
+	Text string
+}
+
+// AddedLines returns the lines of the new file that were added or
+// modified, in ascending order.
+func (f File) AddedLines() []Line {
+	var out []Line
+	for _, h := range f.Hunks {
+		line := h.NewStart
+		for _, l := range h.Lines {
+			switch l[0] {
+			case ' ':
+				line++
+			case '+':
+				out = append(out, Line{Number: line, Text: l[1:]})
+				line++
+			}
+		}
+	}
+	return out
+}
+
+// ParseFiles parses a unified diff of any number of files. Lines outside
+// the file headers and hunks, such as the extended headers of git, are
+// skipped.
+func ParseFiles(r io.Reader) ([]File, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimSuffix(scanner.Text(), "\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var files []File
+	for i := 0; i < len(lines); i++ {
+		if i+1 >= len(lines) || !strings.HasPrefix(lines[i], "--- ") || !strings.HasPrefix(lines[i+1], "+++ ") {
+			continue
+		}
+		f := File{OldName: fileName(lines[i][4:], "a/"), NewName: fileName(lines[i+1][4:], "b/")}
+		hunks, n := ReadHunks(lines[i:])
+		if n == 0 {
+			if i+2 < len(lines) && strings.HasPrefix(lines[i+2], "@@") {
+				return nil, fmt.Errorf("line %d: malformed hunk", i+3)
+			}
+			n = 2
+		}
+		f.Hunks = hunks
+		files = append(files, f)
+		i += n - 1
+	}
+	return files, nil
+}
+
+// fileName returns the path named by a "---" or "+++" header, without a
+// trailing timestamp or the given git prefix, or "" for /dev/null.
+func fileName(header, prefix string) string {
+	name, _, _ := strings.Cut(header, "\t")
+	if name == "/dev/null" {
+		return ""
+	}
+	if unquoted, ok := strings.CutPrefix(name, `"`); ok {
+		name = strings.TrimSuffix(unquoted, `"`)
+	}
+	return strings.TrimPrefix(name, prefix)
+}
@@ -0,0 +1,91 @@
+package diff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const gitDiff = `diff --git a/foo/x.go b/foo/x.go
+index 1111111..2222222 100644
+--- a/foo/x.go
++++ b/foo/x.go
+@@ -2,3 +2,4 @@ package foo
+ func A() {
+-	a()
++	b()
++	c()
+ }
+@@ -10,2 +11,3 @@ func B() {
+ 	d()
+
++	e()
+diff --git a/logo.png b/logo.png
+new file mode 100644
+index 0000000..3333333
+Binary files /dev/null and b/logo.png differ
+diff --git a/foo/y.go b/foo/y.go
+new file mode 100644
+--- /dev/null
++++ b/foo/y.go
+@@ -0,0 +1,2 @@
++package foo
++
+diff --git a/foo/z.go b/foo/z.go
+deleted file mode 100644
+--- a/foo/z.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package foo
+`
+
+func TestParseFiles(t *testing.T) {
+	files, err := ParseFiles(strings.NewReader(gitDiff))
+	if err != nil {
+		t.Fatalf("ParseFiles returned error: %v", err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("got %d files, want 3: %+v", len(files), files)
+	}
+
+	tests := []struct {
+		old, new string
+		hunks    int
+		added    []int
+	}{
+		{"foo/x.go", "foo/x.go", 2, []int{3, 4, 13}},
+		{"", "foo/y.go", 1, []int{1, 2}},
+		{"foo/z.go", "", 1, nil},
+	}
+	for i, tt := range tests {
+		f := files[i]
+		if f.OldName != tt.old || f.NewName != tt.new || len(f.Hunks) != tt.hunks {
+			t.Errorf("file %d = %q -> %q with %d hunks, want %q -> %q with %d", i, f.OldName, f.NewName, len(f.Hunks), tt.old, tt.new, tt.hunks)
+		}
+		var got []int
+		for _, l := range f.AddedLines() {
+			got = append(got, l.Number)
+		}
+		if !reflect.DeepEqual(got, tt.added) {
+			t.Errorf("file %d added lines = %v, want %v", i, got, tt.added)
+		}
+	}
+}
+
+func TestParseFiles_PlainDiff(t *testing.T) {
+	data := "--- x.go\t2024-01-01 00:00:00\n+++ x.go\t2024-01-02 00:00:00\n@@ -1 +1 @@\n-a\n+b\n"
+	files, err := ParseFiles(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseFiles returned error: %v", err)
+	}
+	if len(files) != 1 || files[0].NewName != "x.go" || !reflect.DeepEqual(files[0].AddedLines(), []Line{{1, "b"}}) {
+		t.Errorf("files = %+v", files)
+	}
+}
+
+func TestParseFiles_Malformed(t *testing.T) {
+	data := "--- a/x.go\n+++ b/x.go\n@@ -1,2 +1,2 @@\n-a\n"
+	if _, err := ParseFiles(strings.NewReader(data)); err == nil {
+		t.Error("expected error for a truncated hunk")
+	}
+}
@@ -0,0 +1,133 @@
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Hunk is a hunk of a unified diff.
+type Hunk struct {
+	// OldStart and OldLines delimit the lines of the original file the
+	// hunk covers. OldStart is 1-based, or the line after which lines are
+	// inserted when OldLines is 0.
+	OldStart, OldLines int
+	// NewStart and NewLines delimit the lines of the new file the hunk
+	// covers.
+	NewStart, NewLines int
+	// Lines are the lines of the hunk, each prefixed with ' ' for
+	// context, '-' for a removed line or '+' for an added line.
+	Lines []string
+}
+
+// hunkHeaderRe matches a hunk header, "@@ -1,3 +1,4 @@".
+var hunkHeaderRe = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// ParseHunkHeader parses a hunk header into an empty hunk.
+func ParseHunkHeader(line string) (Hunk, bool) {
+	m := hunkHeaderRe.FindStringSubmatch(line)
+	if m == nil {
+		return Hunk{}, false
+	}
+	count := func(s string) int {
+		if s == "" {
+			return 1
+		}
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+	h := Hunk{OldLines: count(m[2]), NewLines: count(m[4])}
+	h.OldStart, _ = strconv.Atoi(m[1])
+	h.NewStart, _ = strconv.Atoi(m[3])
+	return h, true
+}
+
+// ReadHunks reads the hunks of a unified diff at the start of lines, after
+// optional "---" and "+++" file header lines. It returns the hunks and the
+// number of lines they span. Empty lines within a hunk are read as empty
+// context lines, as printed by tools that trim trailing whitespace.
+func ReadHunks(lines []string) ([]Hunk, int) {
+	i := 0
+	if i+1 < len(lines) && strings.HasPrefix(lines[i], "--- ") && strings.HasPrefix(lines[i+1], "+++ ") {
+		i += 2
+	}
+
+	var result []Hunk
+	for i < len(lines) {
+		h, ok := ParseHunkHeader(lines[i])
+		if !ok {
+			break
+		}
+		j := i + 1
+		old, added := 0, 0
+		for j < len(lines) && (old < h.OldLines || added < h.NewLines) {
+			line := lines[j]
+			if line == "" {
+				line = " "
+			}
+			switch line[0] {
+			case ' ':
+				old++
+				added++
+			case '-':
+				old++
+			case '+':
+				added++
+			case '\\':
+				// "\ No newline at end of file"
+			default:
+				return result, i
+			}
+			h.Lines = append(h.Lines, line)
+			j++
+		}
+		if old != h.OldLines || added != h.NewLines {
+			break
+		}
+		result = append(result, h)
+		i = j
+	}
+	if len(result) == 0 {
+		return nil, 0
+	}
+	return result, i
+}
+
+// ErrMismatch is returned by Apply when a hunk does not match the lines
+// it is applied to.
+var ErrMismatch = errors.New("diff does not apply")
+
+// Apply applies hs, in order, to the lines of the original file and
+// returns the lines of the new file.
+func Apply(lines []string, hs []Hunk) ([]string, error) {
+	var out []string
+	next := 0
+	for _, h := range hs {
+		start := h.OldStart - 1
+		if h.OldLines == 0 {
+			start = h.OldStart
+		}
+		if start < next || start > len(lines) {
+			return nil, fmt.Errorf("%w: hunk at line %d out of range", ErrMismatch, h.OldStart)
+		}
+		out = append(out, lines[next:start]...)
+		next = start
+		for _, line := range h.Lines {
+			switch line[0] {
+			case ' ', '-':
+				if next >= len(lines) || lines[next] != line[1:] {
+					return nil, fmt.Errorf("%w: line %d differs", ErrMismatch, next+1)
+				}
+				if line[0] == ' ' {
+					out = append(out, line[1:])
+				}
+				next++
+			case '+':
+				out = append(out, line[1:])
+			}
+		}
+	}
+	return append(out, lines[next:]...), nil
+}
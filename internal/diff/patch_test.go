@@ -0,0 +1,84 @@
+package diff
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseHunkHeader(t *testing.T) {
+	tests := []struct {
+		line string
+		want Hunk
+		ok   bool
+	}{
+		{"@@ -1,3 +1,4 @@", Hunk{OldStart: 1, OldLines: 3, NewStart: 1, NewLines: 4}, true},
+		{"@@ -5 +5 @@ func F()", Hunk{OldStart: 5, OldLines: 1, NewStart: 5, NewLines: 1}, true},
+		{"@@ -0,0 +1,2 @@", Hunk{NewStart: 1, NewLines: 2}, true},
+		{"--- a", Hunk{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseHunkHeader(tt.line)
+		if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseHunkHeader(%q) = %+v, %v, want %+v, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestReadHunks(t *testing.T) {
+	lines := []string{
+		"--- expected",
+		"+++ actual",
+		"@@ -1,3 +1,3 @@",
+		" a",
+		"-b",
+		"+B",
+		"",
+		"@@ -9 +9,2 @@",
+		" i",
+		"+j",
+		"",
+		"You can run 'go test . -update'",
+	}
+
+	hunks, n := ReadHunks(lines)
+	if n != 10 {
+		t.Errorf("ReadHunks() consumed %d lines, want 10", n)
+	}
+	want := []Hunk{
+		{OldStart: 1, OldLines: 3, NewStart: 1, NewLines: 3, Lines: []string{" a", "-b", "+B", " "}},
+		{OldStart: 9, OldLines: 1, NewStart: 9, NewLines: 2, Lines: []string{" i", "+j"}},
+	}
+	if !reflect.DeepEqual(hunks, want) {
+		t.Errorf("ReadHunks() = %+v, want %+v", hunks, want)
+	}
+
+	if hunks, n := ReadHunks([]string{"--- a", "+++ b", "not a hunk"}); hunks != nil || n != 0 {
+		t.Errorf("ReadHunks() without hunks = %+v, %d", hunks, n)
+	}
+}
+
+func TestApply(t *testing.T) {
+	old := []string{"a", "b", "", "d", "e"}
+	hs, _ := ReadHunks([]string{
+		"@@ -1,3 +1,3 @@",
+		" a",
+		"-b",
+		"+B",
+		"",
+		"@@ -5,0 +6,1 @@",
+		"+f",
+	})
+
+	got, err := Apply(old, hs)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if want := []string{"a", "B", "", "d", "e", "f"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply() = %q, want %q", got, want)
+	}
+
+	if _, err := Apply([]string{"x", "y"}, hs); !errors.Is(err, ErrMismatch) {
+		t.Errorf("Apply() to other lines error = %v, want ErrMismatch", err)
+	}
+}
@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/diff"
+	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
+	"github.com/ivuorinen/go-test-sarif-action/internal/source"
+)
+
+// exampleMismatch is the output of an Example function that differs from
+// its "// Output:" comment.
+type exampleMismatch struct {
+	// Got is the output the example printed.
+	Got string
+	// Want is the output documented in the example.
+	Want string
+	// Unordered is set for "// Unordered output:" comments.
+	Unordered bool
+}
+
+// parseExampleOutput extracts the got and want sections printed by the
+// testing package when an example's output does not match.
+func parseExampleOutput(text string) (exampleMismatch, bool) {
+	lines := diff.SplitLines(text)
+	gotAt := slices.Index(lines, "got:")
+	if gotAt < 0 {
+		return exampleMismatch{}, false
+	}
+
+	var m exampleMismatch
+	for i := len(lines) - 1; i > gotAt; i-- {
+		switch lines[i] {
+		case "want:", "want (unordered):":
+			m.Unordered = lines[i] == "want (unordered):"
+			m.Got = strings.TrimSpace(strings.Join(lines[gotAt+1:i], "\n"))
+			m.Want = strings.TrimSpace(strings.Join(lines[i+1:], "\n"))
+			return m, true
+		}
+	}
+	return exampleMismatch{}, false
+}
+
+// describeExampleFailure renders the output mismatch of a failed example
+// as a diff and locates the result at its "// Output:" comment.
+func describeExampleFailure(result *sarif.Result, c *testCase, resolver *source.Resolver) {
+	m, ok := parseExampleOutput(c.outputText())
+	if !ok {
+		return
+	}
+
+	want, got := diff.SplitLines(m.Want), diff.SplitLines(m.Got)
+	comment := "// Output:"
+	if m.Unordered {
+		// Line order does not matter, so compare the sorted lines.
+		slices.Sort(want)
+		slices.Sort(got)
+		comment = "// Unordered output:"
+	}
+	d := diff.Unified("want", "got", want, got, 3)
+
+	result.Message = fmt.Sprintf("%s output does not match its %s comment\n\n%s", c.test, comment, d)
+	result.Markdown = fmt.Sprintf("`%s` output does not match its `%s` comment\n\n```diff\n%s```\n", c.test, comment, d)
+	if result.Properties == nil {
+		result.Properties = map[string]any{}
+	}
+	result.Properties["exampleOutput"] = map[string]any{
+		"got":       m.Got,
+		"want":      m.Want,
+		"unordered": m.Unordered,
+	}
+
+	if start, end, ok := resolver.FindExampleOutput(c.pkg, c.test); ok {
+		result.PhysicalLocation = &sarif.PhysicalLocation{
+			URI:       resolver.URI(start.File),
+			StartLine: start.Line,
+			EndLine:   end.Line,
+		}
+	}
+}
+
+// isExample reports whether test names an Example function.
+func isExample(test string) bool {
+	return strings.HasPrefix(test, "Example")
+}
@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
+	"github.com/ivuorinen/go-test-sarif-action/internal/testjson"
+)
+
+const exampleSource = `package foo
+
+import "fmt"
+
+func Example_hello() {
+	fmt.Println("hello")
+	// Output:
+	// hello
+	// there
+}
+`
+
+func TestParseExampleOutput(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want exampleMismatch
+		ok   bool
+	}{
+		{
+			name: "ordered",
+			text: "got:\nhello\nworld\nwant:\nhello\nthere\n",
+			want: exampleMismatch{Got: "hello\nworld", Want: "hello\nthere"},
+			ok:   true,
+		},
+		{
+			name: "unordered",
+			text: "got:\na\n\nwant (unordered):\nb\n\n",
+			want: exampleMismatch{Got: "a", Want: "b", Unordered: true},
+			ok:   true,
+		},
+		{
+			name: "want in output",
+			text: "got:\nwant:\nx\nwant:\ny\n",
+			want: exampleMismatch{Got: "want:\nx", Want: "y"},
+			ok:   true,
+		},
+		{
+			name: "not an example mismatch",
+			text: "foo_test.go:12: boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseExampleOutput(tt.text)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("parseExampleOutput() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestDescribeExampleFailure(t *testing.T) {
+	resolver := writeTestModule(t, map[string]string{"foo/example_test.go": exampleSource})
+	events := testOutput("Example_hello", "got:\n", "hello\n", "world\n", "want:\n", "hello\n", "there\n")
+	events = append(events, testjson.TestEvent{Action: "fail", Package: testPkg, Test: "Example_hello"})
+	c := collectTests(events)[0]
+
+	result := sarif.Result{Message: c.message()}
+	describeExampleFailure(&result, c, resolver)
+
+	if !strings.Contains(result.Message, "-there\n+world\n") {
+		t.Errorf("Message = %q, want a diff", result.Message)
+	}
+	if !strings.Contains(result.Markdown, "```diff\n") {
+		t.Errorf("Markdown = %q, want a diff block", result.Markdown)
+	}
+	loc := result.PhysicalLocation
+	if loc == nil || loc.URI != "foo/example_test.go" || loc.StartLine != 7 || loc.EndLine != 9 {
+		t.Errorf("PhysicalLocation = %+v, want foo/example_test.go:7-9", loc)
+	}
+}
+
+func TestDescribeExampleFailure_Panic(t *testing.T) {
+	events := testOutput("Example_hello", "panic: boom\n")
+	c := collectTests(events)[0]
+
+	result := sarif.Result{Message: c.message()}
+	describeExampleFailure(&result, c, writeTestModule(t, map[string]string{}))
+
+	if result.Message != "panic: boom" || result.Markdown != "" {
+		t.Errorf("result = %+v, want unchanged", result)
+	}
+}
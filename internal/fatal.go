@@ -0,0 +1,198 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/gotrace"
+	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
+	"github.com/ivuorinen/go-test-sarif-action/internal/source"
+)
+
+// fatalRule is reported for Go runtime fatal errors.
+var fatalRule = sarif.Rule{
+	ID:          "go-runtime-fatal",
+	Description: "Go runtime fatal error terminated the test binary",
+}
+
+// Runtime fatal error kinds, recorded in the fatalKind result property.
+const (
+	fatalDeadlock      = "deadlock"
+	fatalConcurrentMap = "concurrent-map-access"
+	fatalOutOfMemory   = "out-of-memory"
+	fatalStackOverflow = "stack-overflow"
+	fatalOtherRuntime  = "other"
+)
+
+// goroutineStackLimit caps the frames of a stack, matching the depth of
+// tracebacks printed by the runtime.
+const goroutineStackLimit = 100
+
+var fatalErrorRe = regexp.MustCompile(`(?m)^fatal error: (.+)$`)
+
+// runtimeFatal is an unrecoverable runtime error that killed a test binary.
+type runtimeFatal struct {
+	// Error is the runtime's error text, after "fatal error: ".
+	Error string
+	// Kind classifies Error, as one of the fatal* constants.
+	Kind string
+	// Goroutines is the goroutine dump printed with the error; the
+	// goroutine that failed comes first.
+	Goroutines []gotrace.Goroutine
+	// Tests are the tests that were running, innermost subtests only.
+	Tests []string
+}
+
+// findRuntimeFatal looks for a runtime fatal error in the output of the
+// package of pkg and of its tests that never finished.
+func findRuntimeFatal(pkg *testCase, cases []*testCase) (runtimeFatal, bool) {
+	var b strings.Builder
+	var running []string
+	for _, c := range cases {
+		if c.pkg != pkg.pkg || c.test == "" || c.done() {
+			continue
+		}
+		running = append(running, c.test)
+		for _, line := range c.Output {
+			b.WriteString(line)
+		}
+	}
+	for _, line := range pkg.Output {
+		b.WriteString(line)
+	}
+	output := b.String()
+
+	loc := fatalErrorRe.FindStringSubmatchIndex(output)
+	if loc == nil {
+		return runtimeFatal{}, false
+	}
+	errText := strings.TrimSpace(output[loc[2]:loc[3]])
+
+	return runtimeFatal{
+		Error:      errText,
+		Kind:       fatalKind(errText),
+		Goroutines: gotrace.Parse(output[loc[1]:]),
+		Tests:      innermostTests(running),
+	}, true
+}
+
+// fatalKind classifies the text of a runtime fatal error.
+func fatalKind(errText string) string {
+	switch {
+	case strings.Contains(errText, "deadlock"):
+		return fatalDeadlock
+	case strings.HasPrefix(errText, "concurrent map"):
+		return fatalConcurrentMap
+	case strings.Contains(errText, "out of memory"):
+		return fatalOutOfMemory
+	case strings.Contains(errText, "stack overflow"):
+		return fatalStackOverflow
+	}
+	return fatalOtherRuntime
+}
+
+// innermostTests drops tests whose subtests are also listed.
+func innermostTests(tests []string) []string {
+	var inner []string
+	for _, t := range tests {
+		parent := false
+		for _, other := range tests {
+			if strings.HasPrefix(other, t+"/") {
+				parent = true
+				break
+			}
+		}
+		if !parent {
+			inner = append(inner, t)
+		}
+	}
+	return inner
+}
+
+// fatalResults returns one result per test that was running when the
+// runtime error occurred, or a single package result if none was.
+func fatalResults(f runtimeFatal, pkg string, resolver *source.Resolver) []sarif.Result {
+	tests := f.Tests
+	if len(tests) == 0 {
+		tests = []string{""}
+	}
+
+	stacks := goroutineStacks(f.Goroutines, resolver)
+	var results []sarif.Result
+	for _, test := range tests {
+		where := "outside of any test"
+		if test != "" {
+			where = "while " + test + " was running"
+		}
+		result := sarif.Result{
+			RuleID: fatalRule.ID,
+			Level:  "error",
+			Message: fmt.Sprintf("fatal error: %s\n\nThe Go runtime terminated the test binary %s.",
+				f.Error, where),
+			Location: &sarif.LogicalLocation{Module: pkg, Function: test},
+			Properties: map[string]any{
+				"fatalKind":  f.Kind,
+				"fatalError": f.Error,
+			},
+			Stacks: stacks,
+		}
+		if test != "" {
+			result.Properties["test"] = test
+		}
+
+		if len(f.Goroutines) > 0 {
+			if frame, ok := frameInPackage(f.Goroutines[0], pkg); ok {
+				result.PhysicalLocation = &sarif.PhysicalLocation{URI: resolver.URI(frame.File), StartLine: frame.Line}
+			}
+		}
+		if result.PhysicalLocation == nil && test != "" {
+			if pos := findTestFunc(resolver, pkg, test); pos != nil {
+				result.PhysicalLocation = &sarif.PhysicalLocation{
+					URI:         resolver.URI(pos.File),
+					StartLine:   pos.Line,
+					StartColumn: pos.Column,
+				}
+			}
+		}
+
+		results = append(results, result)
+	}
+	return results
+}
+
+// frameInPackage returns the innermost frame of g that belongs to pkg.
+func frameInPackage(g gotrace.Goroutine, pkg string) (gotrace.Frame, bool) {
+	for _, frame := range g.Frames {
+		if fpkg, _ := gotrace.SplitFunction(frame.Function); fpkg == pkg {
+			return frame, true
+		}
+	}
+	return gotrace.Frame{}, false
+}
+
+// goroutineStacks converts goroutines to SARIF stacks, listing the frame
+// that created each goroutine last.
+func goroutineStacks(goroutines []gotrace.Goroutine, resolver *source.Resolver) []sarif.Stack {
+	stacks := make([]sarif.Stack, 0, len(goroutines))
+	for _, g := range goroutines {
+		st := sarif.Stack{Message: fmt.Sprintf("goroutine %d [%s]", g.ID, g.State)}
+		frames := g.Frames
+		if g.CreatedBy != nil {
+			frames = append(frames[:len(frames):len(frames)], *g.CreatedBy)
+		}
+		for i, frame := range frames {
+			if i == goroutineStackLimit {
+				break
+			}
+			module, _ := gotrace.SplitFunction(frame.Function)
+			sf := sarif.StackFrame{Function: frame.Function, Module: module, Line: frame.Line, ThreadID: g.ID}
+			if frame.File != "" {
+				sf.URI = resolver.URI(frame.File)
+			}
+			st.Frames = append(st.Frames, sf)
+		}
+		stacks = append(stacks, st)
+	}
+	return stacks
+}
@@ -0,0 +1,111 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/source"
+	"github.com/ivuorinen/go-test-sarif-action/internal/testjson"
+)
+
+// testOutput returns output events of test for lines.
+func testOutput(test string, lines ...string) []testjson.TestEvent {
+	var events []testjson.TestEvent
+	for _, line := range lines {
+		events = append(events, testjson.TestEvent{Action: "output", Package: testPkg, Test: test, Output: line})
+	}
+	return events
+}
+
+func TestFindRuntimeFatal(t *testing.T) {
+	events := []testjson.TestEvent{
+		{Action: "run", Package: testPkg, Test: "TestDone"},
+		{Action: "pass", Package: testPkg, Test: "TestDone"},
+		{Action: "run", Package: testPkg, Test: "TestMap"},
+		{Action: "run", Package: testPkg, Test: "TestMap/sub"},
+	}
+	events = append(events, testOutput("TestMap/sub",
+		"fatal error: concurrent map writes\n",
+		"\n",
+		"goroutine 10 [running]:\n",
+		"internal/runtime/maps.fatal({0x559f6b?, 0x0?})\n",
+		"\t/usr/local/go/src/runtime/panic.go:1195 +0x18\n",
+		"example.com/app/foo.TestMap.func1()\n",
+		"\t/src/app/foo/foo_test.go:26 +0x65\n",
+		"created by example.com/app/foo.TestMap in goroutine 7\n",
+		"\t/src/app/foo/foo_test.go:23 +0x48\n",
+	)...)
+	events = append(events, testjson.TestEvent{Action: "fail", Package: testPkg})
+
+	cases := collectTests(events)
+	f, ok := findRuntimeFatal(cases[len(cases)-1], cases)
+	if !ok {
+		t.Fatal("findRuntimeFatal() found nothing")
+	}
+
+	if f.Error != "concurrent map writes" || f.Kind != fatalConcurrentMap {
+		t.Errorf("fatal = %q (%s), want concurrent map writes", f.Error, f.Kind)
+	}
+	if len(f.Tests) != 1 || f.Tests[0] != "TestMap/sub" {
+		t.Errorf("Tests = %v, want [TestMap/sub]", f.Tests)
+	}
+	if len(f.Goroutines) != 1 || f.Goroutines[0].ID != 10 {
+		t.Errorf("Goroutines = %+v, want goroutine 10", f.Goroutines)
+	}
+
+	results := fatalResults(f, testPkg, source.NewResolver(""))
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	res := results[0]
+	if res.RuleID != fatalRule.ID || res.Location.Function != "TestMap/sub" {
+		t.Errorf("result = %s at %+v", res.RuleID, res.Location)
+	}
+	if res.PhysicalLocation == nil || res.PhysicalLocation.StartLine != 26 {
+		t.Errorf("PhysicalLocation = %+v, want line 26", res.PhysicalLocation)
+	}
+	if len(res.Stacks) != 1 || len(res.Stacks[0].Frames) != 3 {
+		t.Errorf("Stacks = %+v, want one stack with created-by frame", res.Stacks)
+	}
+	if !strings.Contains(res.Message, "while TestMap/sub was running") {
+		t.Errorf("Message = %q", res.Message)
+	}
+}
+
+func TestFindRuntimeFatal_None(t *testing.T) {
+	events := append(pkgOutput("panic: boom\n"), testjson.TestEvent{Action: "fail", Package: testPkg})
+	cases := collectTests(events)
+
+	if _, ok := findRuntimeFatal(cases[0], cases); ok {
+		t.Error("findRuntimeFatal() found a fatal error in a panic")
+	}
+}
+
+func TestFatalResults_OutsideTests(t *testing.T) {
+	f := runtimeFatal{Error: "all goroutines are asleep - deadlock!", Kind: fatalDeadlock}
+
+	results := fatalResults(f, testPkg, source.NewResolver(""))
+	if len(results) != 1 || results[0].Location.Function != "" {
+		t.Fatalf("results = %+v, want one package result", results)
+	}
+	if results[0].Properties["fatalKind"] != fatalDeadlock {
+		t.Errorf("fatalKind = %v, want %v", results[0].Properties["fatalKind"], fatalDeadlock)
+	}
+}
+
+func TestFatalKind(t *testing.T) {
+	tests := map[string]string{
+		"all goroutines are asleep - deadlock!":      fatalDeadlock,
+		"concurrent map writes":                      fatalConcurrentMap,
+		"concurrent map read and map write":          fatalConcurrentMap,
+		"runtime: out of memory":                     fatalOutOfMemory,
+		"stack overflow":                             fatalStackOverflow,
+		"sync: unlock of unlocked mutex":             fatalOtherRuntime,
+		"unexpected signal during runtime execution": fatalOtherRuntime,
+	}
+	for errText, want := range tests {
+		if got := fatalKind(errText); got != want {
+			t.Errorf("fatalKind(%q) = %q, want %q", errText, got, want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/recognize"
+	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
+	"github.com/ivuorinen/go-test-sarif-action/internal/source"
+)
+
+// flakyRule is reported for tests that both passed and failed in one
+// input, as with -count, reruns or merged shard logs.
+var flakyRule = sarif.Rule{
+	ID:          "go-test-flaky",
+	Description: "test both passed and failed",
+}
+
+// outcomes counts the finished runs of a test.
+type outcomes struct {
+	passed, failed int
+}
+
+// flaky reports whether the test both passed and failed.
+func (o outcomes) flaky() bool {
+	return o.passed > 0 && o.failed > 0
+}
+
+// countOutcomes counts the passed and failed runs of each test and
+// package.
+func countOutcomes(cases []*testCase) map[testKey]outcomes {
+	counts := make(map[testKey]outcomes)
+	for _, c := range cases {
+		o := counts[c.testKey]
+		switch c.Action {
+		case "pass":
+			o.passed++
+		case "fail":
+			o.failed++
+		default:
+			continue
+		}
+		counts[c.testKey] = o
+	}
+	return counts
+}
+
+// failedOnlyByFlakyTests returns the packages and parent tests whose
+// failed tests are all flaky, so that they failed because of them alone.
+func failedOnlyByFlakyTests(cases []*testCase, counts map[testKey]outcomes) map[testKey]bool {
+	explained := make(map[testKey]bool)
+	unexplained := make(map[testKey]bool)
+	for _, c := range cases {
+		if c.test == "" {
+			continue
+		}
+		flakyFailure := c.Action == "fail" && counts[c.testKey].flaky()
+		// A test that never finished failed its parents.
+		failure := !c.done() || c.Action == "fail" && !flakyFailure
+		if !flakyFailure && !failure {
+			continue
+		}
+
+		parent := c.test
+		for {
+			i := strings.LastIndexByte(parent, '/')
+			if i < 0 {
+				parent = ""
+			} else {
+				parent = parent[:i]
+			}
+			k := testKey{pkg: c.pkg, test: parent}
+			if failure {
+				unexplained[k] = true
+			} else {
+				explained[k] = true
+			}
+			if parent == "" {
+				break
+			}
+		}
+	}
+	for k := range unexplained {
+		delete(explained, k)
+	}
+	return explained
+}
+
+// flakyResult reports the failed runs of a flaky test or package. It is
+// located where the first run failed.
+func flakyResult(failures []*testCase, o outcomes, rs []recognize.Recognizer, resolver *source.Resolver) sarif.Result {
+	first := failures[0]
+	name := first.test
+	if name == "" {
+		name = "package " + first.pkg
+	}
+	runs := o.passed + o.failed
+	summary := fmt.Sprintf("%s is flaky: failed %d of %d runs", name, o.failed, runs)
+
+	var text, md strings.Builder
+	text.WriteString(summary)
+	fmt.Fprintf(&md, "%s\n", summary)
+	for i, c := range failures {
+		output := c.message()
+		fmt.Fprintf(&text, "\n\nFailure %d:\n%s", i+1, output)
+		fmt.Fprintf(&md, "\n**Failure %d**\n\n```\n%s\n```\n", i+1, output)
+	}
+
+	result := sarif.Result{
+		RuleID:   flakyRule.ID,
+		Level:    "warning",
+		Message:  text.String(),
+		Markdown: md.String(),
+		Location: &sarif.LogicalLocation{Module: first.pkg, Function: first.test},
+		Properties: map[string]any{
+			"passes":   o.passed,
+			"failures": o.failed,
+		},
+	}
+	if first.test != "" {
+		var located sarif.Result
+		describeAssertionFailure(&located, first, rs, resolver)
+		result.PhysicalLocation = located.PhysicalLocation
+	}
+	return result
+}
@@ -0,0 +1,110 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/testjson"
+)
+
+// runEvents returns the events of one run of test with the given outcome.
+func runEvents(test, action string, output ...string) []testjson.TestEvent {
+	events := []testjson.TestEvent{{Action: "run", Package: testPkg, Test: test}}
+	events = append(events, testOutput(test, output...)...)
+	return append(events, testjson.TestEvent{Action: action, Package: testPkg, Test: test})
+}
+
+func TestBuildReport_FlakyCount(t *testing.T) {
+	resolver := writeTestModule(t, map[string]string{"foo/foo_test.go": "package foo\n"})
+	var events []testjson.TestEvent
+	events = append(events, runEvents("TestFlaky", "pass")...)
+	events = append(events, runEvents("TestFlaky", "fail", "    foo_test.go:1: timeout\n")...)
+	events = append(events, runEvents("TestFlaky", "fail", "    foo_test.go:1: reset\n")...)
+	events = append(events, runEvents("TestBroken", "fail", "    foo_test.go:1: broken\n")...)
+	events = append(events, runEvents("TestBroken", "fail", "    foo_test.go:1: broken\n")...)
+	events = append(events, pkgOutput("FAIL\n")...)
+	events = append(events, testjson.TestEvent{Action: "fail", Package: testPkg})
+	in := input{Path: "count.json", Events: events}
+
+	results := buildReport([]input{in}, ConvertOptions{SourceRoot: resolver.Root()}).Results
+
+	var flaky, errors int
+	for _, r := range results {
+		switch r.RuleID {
+		case flakyRule.ID:
+			flaky++
+			if r.Level != "warning" || r.Location.Function != "TestFlaky" {
+				t.Errorf("flaky result = %+v", r)
+			}
+			if !strings.HasPrefix(r.Message, "TestFlaky is flaky: failed 2 of 3 runs") ||
+				!strings.Contains(r.Message, "timeout") || !strings.Contains(r.Message, "reset") {
+				t.Errorf("flaky message = %q, want the counts and both failures", r.Message)
+			}
+			if r.Properties["passes"] != 1 || r.Properties["failures"] != 2 {
+				t.Errorf("flaky properties = %v", r.Properties)
+			}
+			if r.PhysicalLocation == nil || r.PhysicalLocation.URI != "foo/foo_test.go" {
+				t.Errorf("flaky location = %+v, want the first failure", r.PhysicalLocation)
+			}
+		case failureRule.ID:
+			errors++
+		}
+	}
+	// TestBroken failed every run, so it and the package stay errors.
+	if flaky != 1 || errors != 2 {
+		t.Errorf("got %d flaky and %d failure results, want 1 and 2: %+v", flaky, errors, results)
+	}
+}
+
+func TestBuildReport_FlakyRerun(t *testing.T) {
+	var events []testjson.TestEvent
+	events = append(events, testjson.TestEvent{Action: "start", Package: testPkg})
+	events = append(events, runEvents("TestFlaky", "fail", "    foo_test.go:1: timeout\n")...)
+	events = append(events, testjson.TestEvent{Action: "fail", Package: testPkg})
+	// gotestsum --rerun-fails appends the rerun of the failed tests.
+	events = append(events, testjson.TestEvent{Action: "start", Package: testPkg})
+	events = append(events, runEvents("TestFlaky", "pass")...)
+	events = append(events, testjson.TestEvent{Action: "pass", Package: testPkg})
+	in := input{Path: "rerun.json", Events: events}
+
+	results := buildReport([]input{in}, ConvertOptions{}).Results
+
+	if len(results) != 1 || results[0].RuleID != flakyRule.ID {
+		t.Fatalf("results = %+v, want a single flaky result", results)
+	}
+}
+
+func TestFailedOnlyByFlakyTests(t *testing.T) {
+	var events []testjson.TestEvent
+	events = append(events, runEvents("TestFlaky", "fail")...)
+	events = append(events, runEvents("TestFlaky", "pass")...)
+	events = append(events, testjson.TestEvent{Action: "run", Package: testPkg, Test: "TestHung"})
+	events = append(events, testjson.TestEvent{Action: "fail", Package: testPkg})
+
+	cases := collectTests(events)
+	pkg := testKey{pkg: testPkg}
+	counts := countOutcomes(cases)
+	if failedOnlyByFlakyTests(cases, counts)[pkg] {
+		t.Error("package with an unfinished test reported as failed only by flaky tests")
+	}
+	if !failedOnlyByFlakyTests(cases[:2], counts)[pkg] {
+		t.Error("package with only flaky failures not recognized")
+	}
+}
+
+func TestBuildReport_FlakySubtest(t *testing.T) {
+	var events []testjson.TestEvent
+	for _, action := range []string{"fail", "pass"} {
+		events = append(events, testjson.TestEvent{Action: "run", Package: testPkg, Test: "TestA"})
+		events = append(events, runEvents("TestA/x", action, "    foo_test.go:1: timeout\n")...)
+		events = append(events, testjson.TestEvent{Action: action, Package: testPkg, Test: "TestA"})
+	}
+	events = append(events, testjson.TestEvent{Action: "fail", Package: testPkg})
+	in := input{Path: "count.json", Events: events}
+
+	results := buildReport([]input{in}, ConvertOptions{}).Results
+
+	if len(results) != 1 || results[0].RuleID != flakyRule.ID || results[0].Location.Function != "TestA/x" {
+		t.Fatalf("results = %+v, want a single flaky result for TestA/x", results)
+	}
+}
@@ -0,0 +1,135 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/ginkgojson"
+	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
+	"github.com/ivuorinen/go-test-sarif-action/internal/source"
+)
+
+// ginkgoStateRules maps the spec states of a Ginkgo report that are not
+// passing to their rules. Failed specs share the go test failure rule.
+var ginkgoStateRules = map[string]sarif.Rule{
+	ginkgojson.StateFailed: failureRule,
+	ginkgojson.StatePanicked: {
+		ID:          "ginkgo-spec-panicked",
+		Description: "Ginkgo spec panicked",
+	},
+	ginkgojson.StateTimedOut: {
+		ID:          "ginkgo-spec-timedout",
+		Description: "Ginkgo spec timed out",
+	},
+	ginkgojson.StateInterrupted: {
+		ID:          "ginkgo-spec-interrupted",
+		Description: "Ginkgo spec was interrupted",
+	},
+	ginkgojson.StateAborted: {
+		ID:          "ginkgo-spec-aborted",
+		Description: "Ginkgo spec aborted the suite",
+	},
+}
+
+// addGinkgoResults adds the specs of a Ginkgo JSON report that did not
+// pass to the report, and suites that failed for reasons outside of any
+// spec, such as programmatic focus.
+func addGinkgoResults(b *reportBuilder, in input, resolver *source.Resolver) {
+	for _, suite := range in.Ginkgo {
+		module := suite.SuiteDescription
+		if pkg, ok := resolver.ImportPath(suite.SuitePath); ok {
+			module = pkg
+		}
+
+		for _, spec := range suite.SpecReports {
+			rule, ok := ginkgoStateRules[spec.State]
+			if !ok {
+				continue
+			}
+			b.addRule(rule)
+			b.add(ginkgoResult(spec, rule, module, suite, resolver), in.Path)
+		}
+
+		if !suite.SuiteSucceeded && len(suite.SpecialSuiteFailureReasons) > 0 {
+			b.add(sarif.Result{
+				RuleID:   failureRule.ID,
+				Level:    "error",
+				Message:  fmt.Sprintf("suite %s failed: %s", suite.SuiteDescription, strings.Join(suite.SpecialSuiteFailureReasons, "; ")),
+				Location: &sarif.LogicalLocation{Module: module},
+			}, in.Path)
+		}
+	}
+}
+
+// ginkgoResult returns the result of a spec that did not pass. The spec's
+// full text is its logical location and its labels, with those of its
+// containers and suite, become result tags.
+func ginkgoResult(spec ginkgojson.SpecReport, rule sarif.Rule, module string, suite ginkgojson.Report, resolver *source.Resolver) sarif.Result {
+	name := spec.FullText()
+	if name == "" {
+		// Suite-level nodes, such as BeforeSuite, have no text.
+		name = spec.LeafNodeType
+	}
+
+	result := sarif.Result{
+		RuleID:   rule.ID,
+		Level:    "error",
+		Location: &sarif.LogicalLocation{Module: module, Function: name},
+		Properties: map[string]any{
+			"state": spec.State,
+			"suite": suite.SuiteDescription,
+		},
+	}
+
+	var message []string
+	location := spec.LeafNodeLocation
+	if f := spec.Failure; f != nil {
+		message = append(message, f.Message)
+		if f.ForwardedPanic != "" {
+			message = append(message, f.ForwardedPanic)
+		}
+		if f.Location.FileName != "" {
+			location = f.Location
+		}
+		if f.FailureNodeType != "" {
+			result.Properties["nodeType"] = f.FailureNodeType
+		}
+	}
+	if output := strings.TrimSpace(spec.CapturedGinkgoWriterOutput); output != "" {
+		message = append(message, output)
+	}
+	result.Message = strings.TrimSpace(strings.Join(message, "\n\n"))
+	if result.Message == "" {
+		result.Message = fmt.Sprintf("%s %s", name, spec.State)
+	}
+
+	if location.FileName != "" {
+		result.PhysicalLocation = &sarif.PhysicalLocation{
+			URI:       resolver.URI(location.FileName),
+			StartLine: location.LineNumber,
+		}
+	}
+
+	labels := append(suite.SuiteLabels[:len(suite.SuiteLabels):len(suite.SuiteLabels)], spec.Labels()...)
+	if tags := uniqueStrings(labels); len(tags) > 0 {
+		result.Properties["tags"] = tags
+	}
+	if spec.NumAttempts > 1 {
+		result.Properties["attempts"] = spec.NumAttempts
+	}
+	return result
+}
+
+// uniqueStrings returns values without duplicates, in order of first
+// appearance.
+func uniqueStrings(values []string) []string {
+	var unique []string
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
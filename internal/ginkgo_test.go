@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/ginkgojson"
+)
+
+func TestAddGinkgoResults(t *testing.T) {
+	resolver := writeTestModule(t, map[string]string{"books/books_test.go": "package books\n"})
+	testFile := filepath.Join(resolver.Root(), "books", "books_test.go")
+
+	suite := ginkgojson.Report{
+		SuitePath:        filepath.Join(resolver.Root(), "books"),
+		SuiteDescription: "Books Suite",
+		SuiteLabels:      []string{"integration"},
+		SpecReports: []ginkgojson.SpecReport{
+			{
+				ContainerHierarchyTexts:  []string{"Books"},
+				ContainerHierarchyLabels: [][]string{{"slow"}},
+				LeafNodeType:             "It",
+				LeafNodeText:             "is a novel",
+				LeafNodeLabels:           []string{"slow", "db"},
+				LeafNodeLocation:         ginkgojson.Location{FileName: testFile, LineNumber: 20},
+				State:                    ginkgojson.StateFailed,
+				Failure: &ginkgojson.Failure{
+					Message:         "Expected short to equal novel",
+					Location:        ginkgojson.Location{FileName: testFile, LineNumber: 22},
+					FailureNodeType: "It",
+				},
+				CapturedGinkgoWriterOutput: "loading fixtures\n",
+			},
+			{
+				LeafNodeType:     "It",
+				LeafNodeText:     "loads",
+				LeafNodeLocation: ginkgojson.Location{FileName: testFile, LineNumber: 30},
+				State:            ginkgojson.StatePanicked,
+				Failure:          &ginkgojson.Failure{Message: "Test Panicked", ForwardedPanic: "boom"},
+			},
+			{LeafNodeType: "BeforeSuite", State: ginkgojson.StateTimedOut},
+			{LeafNodeType: "It", LeafNodeText: "passes", State: "passed"},
+			{LeafNodeType: "It", LeafNodeText: "is pending", State: "pending"},
+		},
+	}
+	in := input{Path: "report.json", Format: formatGinkgoJSON, Ginkgo: []ginkgojson.Report{suite}}
+
+	results := buildReport([]input{in}, ConvertOptions{SourceRoot: resolver.Root()}).Results
+
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want one per spec that did not pass: %+v", len(results), results)
+	}
+
+	failed := results[0]
+	if failed.RuleID != failureRule.ID || failed.Message != "Expected short to equal novel\n\nloading fixtures" {
+		t.Errorf("failed spec = %s: %q", failed.RuleID, failed.Message)
+	}
+	if failed.Location.Module != "example.com/app/books" || failed.Location.Function != "Books is a novel" {
+		t.Errorf("Location = %+v, want the suite package and spec text", failed.Location)
+	}
+	if loc := failed.PhysicalLocation; loc == nil || loc.URI != "books/books_test.go" || loc.StartLine != 22 {
+		t.Errorf("PhysicalLocation = %+v, want the failure location", loc)
+	}
+	if tags, _ := failed.Properties["tags"].([]string); !slices.Equal(tags, []string{"integration", "slow", "db"}) {
+		t.Errorf("tags = %v, want suite, container and spec labels", tags)
+	}
+
+	panicked := results[1]
+	if panicked.RuleID != "ginkgo-spec-panicked" || !strings.Contains(panicked.Message, "boom") {
+		t.Errorf("panicked spec = %s: %q", panicked.RuleID, panicked.Message)
+	}
+	if loc := panicked.PhysicalLocation; loc == nil || loc.StartLine != 30 {
+		t.Errorf("PhysicalLocation = %+v, want the spec declaration", loc)
+	}
+
+	timedOut := results[2]
+	if timedOut.RuleID != "ginkgo-spec-timedout" || timedOut.Location.Function != "BeforeSuite" || timedOut.Message != "BeforeSuite timedout" {
+		t.Errorf("timed out node = %s %+v: %q", timedOut.RuleID, timedOut.Location, timedOut.Message)
+	}
+}
+
+func TestAddGinkgoResults_SuiteFailure(t *testing.T) {
+	suite := ginkgojson.Report{
+		SuitePath:                  "/elsewhere/books",
+		SuiteDescription:           "Books Suite",
+		SpecialSuiteFailureReasons: []string{"Detected Programmatic Focus - setting exit status to 197"},
+	}
+	in := input{Path: "report.json", Format: formatGinkgoJSON, Ginkgo: []ginkgojson.Report{suite}}
+
+	report := buildReport([]input{in}, ConvertOptions{SourceRoot: t.TempDir()})
+
+	if len(report.Results) != 1 {
+		t.Fatalf("got %d results, want the suite failure", len(report.Results))
+	}
+	r := report.Results[0]
+	if r.Location.Module != "Books Suite" || !strings.Contains(r.Message, "Programmatic Focus") {
+		t.Errorf("result = %+v", r)
+	}
+}
@@ -0,0 +1,133 @@
+// Package ginkgojson provides parsing utilities for Ginkgo v2 JSON reports,
+// as written by ginkgo --json-report.
+package ginkgojson
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Spec states that mark a spec as not passing, as serialized by Ginkgo.
+const (
+	StateFailed      = "failed"
+	StatePanicked    = "panicked"
+	StateTimedOut    = "timedout"
+	StateInterrupted = "interrupted"
+	StateAborted     = "aborted"
+)
+
+// Report is the report of one test suite.
+type Report struct {
+	// SuitePath is the directory of the suite.
+	SuitePath string `json:"SuitePath"`
+	// SuiteDescription is the description passed to RunSpecs.
+	SuiteDescription string `json:"SuiteDescription"`
+	// SuiteLabels are the labels of the whole suite.
+	SuiteLabels []string `json:"SuiteLabels,omitempty"`
+	// SuiteSucceeded reports whether the suite passed.
+	SuiteSucceeded bool `json:"SuiteSucceeded"`
+	// SpecialSuiteFailureReasons explain suite failures not attributable
+	// to a spec, such as programmatic focus.
+	SpecialSuiteFailureReasons []string `json:"SpecialSuiteFailureReasons,omitempty"`
+	// SpecReports are the reports of the specs and suite-level nodes.
+	SpecReports []SpecReport `json:"SpecReports"`
+}
+
+// SpecReport is the report of one spec or suite-level node, such as
+// BeforeSuite.
+type SpecReport struct {
+	// ContainerHierarchyTexts are the texts of the enclosing containers,
+	// outermost first.
+	ContainerHierarchyTexts []string `json:"ContainerHierarchyTexts"`
+	// ContainerHierarchyLabels are the labels of each container.
+	ContainerHierarchyLabels [][]string `json:"ContainerHierarchyLabels"`
+	// LeafNodeType is the kind of node, such as "It" or "BeforeSuite".
+	LeafNodeType string `json:"LeafNodeType"`
+	// LeafNodeLocation is where the leaf node is declared.
+	LeafNodeLocation Location `json:"LeafNodeLocation"`
+	// LeafNodeText is the text of the leaf node.
+	LeafNodeText string `json:"LeafNodeText"`
+	// LeafNodeLabels are the labels of the leaf node.
+	LeafNodeLabels []string `json:"LeafNodeLabels"`
+	// State is the outcome, such as "passed" or one of the State constants.
+	State string `json:"State"`
+	// RunTime is the duration of the spec.
+	RunTime time.Duration `json:"RunTime"`
+	// NumAttempts is the number of times the spec ran.
+	NumAttempts int `json:"NumAttempts"`
+	// Failure describes why the spec did not pass.
+	Failure *Failure `json:"Failure,omitempty"`
+	// CapturedGinkgoWriterOutput is the output written to GinkgoWriter.
+	CapturedGinkgoWriterOutput string `json:"CapturedGinkgoWriterOutput,omitempty"`
+	// CapturedStdOutErr is the captured standard output and error.
+	CapturedStdOutErr string `json:"CapturedStdOutErr,omitempty"`
+}
+
+// FullText returns the container texts and the leaf text joined by
+// spaces, as Ginkgo prints spec names.
+func (s SpecReport) FullText() string {
+	texts := append(s.ContainerHierarchyTexts[:len(s.ContainerHierarchyTexts):len(s.ContainerHierarchyTexts)], s.LeafNodeText)
+	var parts []string
+	for _, t := range texts {
+		if t != "" {
+			parts = append(parts, t)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Labels returns the labels of the spec and its containers, outermost
+// first, without duplicates.
+func (s SpecReport) Labels() []string {
+	var labels []string
+	seen := make(map[string]bool)
+	add := func(ls []string) {
+		for _, l := range ls {
+			if !seen[l] {
+				seen[l] = true
+				labels = append(labels, l)
+			}
+		}
+	}
+	for _, ls := range s.ContainerHierarchyLabels {
+		add(ls)
+	}
+	add(s.LeafNodeLabels)
+	return labels
+}
+
+// Failure describes a failed spec.
+type Failure struct {
+	// Message is the failure message.
+	Message string `json:"Message"`
+	// Location is where the failure occurred.
+	Location Location `json:"Location"`
+	// ForwardedPanic is the panic value of a panicked spec.
+	ForwardedPanic string `json:"ForwardedPanic,omitempty"`
+	// FailureNodeType is the kind of node that failed, such as "It".
+	FailureNodeType string `json:"FailureNodeType"`
+	// FailureNodeLocation is where the failing node is declared.
+	FailureNodeLocation Location `json:"FailureNodeLocation"`
+}
+
+// Location is a source location in a Ginkgo report.
+type Location struct {
+	// FileName is the source file path.
+	FileName string `json:"FileName"`
+	// LineNumber is the 1-based line.
+	LineNumber int `json:"LineNumber"`
+	// FullStackTrace is the stack trace at the location, if captured.
+	FullStackTrace string `json:"FullStackTrace,omitempty"`
+}
+
+// Parse reads and parses a Ginkgo JSON report from r.
+func Parse(r io.Reader) ([]Report, error) {
+	var reports []Report
+	if err := json.NewDecoder(r).Decode(&reports); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return reports, nil
+}
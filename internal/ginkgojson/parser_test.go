@@ -0,0 +1,101 @@
+package ginkgojson
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const ginkgoReport = `[
+  {
+    "SuitePath": "/src/app/books",
+    "SuiteDescription": "Books Suite",
+    "SuiteLabels": ["integration"],
+    "SuiteSucceeded": false,
+    "SuiteHasProgrammaticFocus": false,
+    "SpecialSuiteFailureReasons": null,
+    "PreRunStats": {"TotalSpecs": 2, "SpecsThatWillRun": 2},
+    "StartTime": "2026-10-18T10:00:00Z",
+    "EndTime": "2026-10-18T10:00:01Z",
+    "RunTime": 1000000000,
+    "SpecReports": [
+      {
+        "ContainerHierarchyTexts": ["Books", "Categorizing"],
+        "ContainerHierarchyLocations": [{"FileName": "/src/app/books/books_test.go", "LineNumber": 10}],
+        "ContainerHierarchyLabels": [["slow"], []],
+        "LeafNodeType": "It",
+        "LeafNodeLocation": {"FileName": "/src/app/books/books_test.go", "LineNumber": 20},
+        "LeafNodeLabels": ["slow", "db"],
+        "LeafNodeText": "is a novel",
+        "State": "failed",
+        "StartTime": "2026-10-18T10:00:00Z",
+        "EndTime": "2026-10-18T10:00:00.5Z",
+        "RunTime": 500000000,
+        "ParallelProcess": 1,
+        "NumAttempts": 1,
+        "MaxFlakeAttempts": 0,
+        "Failure": {
+          "Message": "Expected\n    <string>: short\nto equal\n    <string>: novel",
+          "Location": {"FileName": "/src/app/books/books_test.go", "LineNumber": 22, "FullStackTrace": "books.glob..func1()\n"},
+          "FailureNodeContext": "leaf-node",
+          "FailureNodeType": "It",
+          "FailureNodeLocation": {"FileName": "/src/app/books/books_test.go", "LineNumber": 20}
+        },
+        "CapturedGinkgoWriterOutput": "loading fixtures\n"
+      },
+      {
+        "ContainerHierarchyTexts": null,
+        "ContainerHierarchyLocations": null,
+        "ContainerHierarchyLabels": null,
+        "LeafNodeType": "BeforeSuite",
+        "LeafNodeLocation": {"FileName": "/src/app/books/suite_test.go", "LineNumber": 15},
+        "LeafNodeLabels": null,
+        "LeafNodeText": "",
+        "State": "passed",
+        "RunTime": 1000
+      }
+    ]
+  }
+]
+`
+
+func TestParse(t *testing.T) {
+	reports, err := Parse(strings.NewReader(ginkgoReport))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(reports) != 1 {
+		t.Fatalf("got %d reports, want 1", len(reports))
+	}
+
+	r := reports[0]
+	if r.SuiteDescription != "Books Suite" || r.SuitePath != "/src/app/books" || r.SuiteSucceeded {
+		t.Errorf("report = %+v", r)
+	}
+	if len(r.SpecReports) != 2 {
+		t.Fatalf("got %d specs, want 2", len(r.SpecReports))
+	}
+
+	spec := r.SpecReports[0]
+	if spec.State != StateFailed || spec.LeafNodeType != "It" || spec.RunTime != 500*time.Millisecond {
+		t.Errorf("spec = %+v", spec)
+	}
+	if spec.Failure == nil || spec.Failure.Location.LineNumber != 22 || spec.Failure.FailureNodeType != "It" {
+		t.Errorf("failure = %+v", spec.Failure)
+	}
+	if got := spec.FullText(); got != "Books Categorizing is a novel" {
+		t.Errorf("FullText() = %q", got)
+	}
+	if got := strings.Join(spec.Labels(), ","); got != "slow,db" {
+		t.Errorf("Labels() = %q, want slow,db", got)
+	}
+	if got := r.SpecReports[1].FullText(); got != "" {
+		t.Errorf("FullText() = %q, want empty for BeforeSuite", got)
+	}
+}
+
+func TestParse_Malformed(t *testing.T) {
+	if _, err := Parse(strings.NewReader(`[{"SpecReports": 1}]`)); err == nil {
+		t.Error("Parse accepted a malformed report")
+	}
+}
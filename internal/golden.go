@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"os"
+	"strings"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/diff"
+	"github.com/ivuorinen/go-test-sarif-action/internal/recognize"
+	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
+	"github.com/ivuorinen/go-test-sarif-action/internal/source"
+)
+
+// describeGoldenFailure locates a golden-file mismatch at the first
+// differing line of the golden file and, when the actual output is known
+// or can be rebuilt from the diff, proposes a fix replacing the golden
+// contents with it.
+func describeGoldenFailure(result *sarif.Result, f recognize.Failure, pkg string, resolver *source.Resolver) {
+	file, ok := assertionFile(f.Golden, pkg, resolver)
+	if !ok {
+		return
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return
+	}
+
+	var hunks []diff.Hunk
+	if f.Diff != "" {
+		hunks, _ = diff.ReadHunks(strings.Split(f.Diff, "\n"))
+	}
+	uri := resolver.URI(file)
+	line := 1
+	if len(hunks) > 0 && hunks[0].OldStart > 1 {
+		line = hunks[0].OldStart
+	}
+	result.PhysicalLocation = &sarif.PhysicalLocation{URI: uri, StartLine: line}
+
+	actual, ok := goldenActual(string(data), f.Actual, hunks)
+	if !ok {
+		return
+	}
+	result.Fixes = []sarif.Fix{{
+		Description: "Update " + uri + " with the actual output",
+		Changes: []sarif.ArtifactChange{{
+			URI: uri,
+			Replacements: []sarif.Replacement{{
+				ByteOffset:   0,
+				ByteLength:   len(data),
+				InsertedText: actual,
+			}},
+		}},
+	}}
+}
+
+// goldenActual returns the actual output of a golden-file comparison: the
+// printed output, or else the golden contents with the diff applied.
+func goldenActual(golden, printed string, hunks []diff.Hunk) (string, bool) {
+	if printed != "" {
+		return printed, true
+	}
+	if len(hunks) == 0 {
+		return "", false
+	}
+
+	out, err := diff.Apply(diff.SplitLines(golden), hunks)
+	if err != nil {
+		return "", false
+	}
+	actual := strings.Join(out, "\n")
+	if strings.HasSuffix(golden, "\n") && actual != "" {
+		actual += "\n"
+	}
+	return actual, true
+}
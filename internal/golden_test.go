@@ -0,0 +1,111 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/diff"
+	"github.com/ivuorinen/go-test-sarif-action/internal/recognize"
+	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
+	"github.com/ivuorinen/go-test-sarif-action/internal/testjson"
+)
+
+func TestDescribeGoldenFailure(t *testing.T) {
+	golden := "<ul>\n<li>a</li>\n</ul>\n"
+	resolver := writeTestModule(t, map[string]string{
+		"foo/render_test.go":       "package foo\n",
+		"foo/testdata/list.golden": golden,
+	})
+	events := testOutput("TestRender",
+		"=== RUN   TestRender\n",
+		"    render_test.go:21: assertion failed: \n",
+		"        --- expected\n",
+		"        +++ actual\n",
+		"        @@ -1,3 +1,3 @@\n",
+		"         <ul>\n",
+		"        -<li>a</li>\n",
+		"        +<li>b</li>\n",
+		"         </ul>\n",
+		"        \n",
+		"        You can run 'go test . -update' to automatically update testdata/list.golden to the new expected value.'\n",
+		"--- FAIL: TestRender (0.00s)\n",
+	)
+	events = append(events, testjson.TestEvent{Action: "fail", Package: testPkg, Test: "TestRender"})
+	c := collectTests(events)[0]
+
+	result := sarif.Result{Message: c.message()}
+	describeAssertionFailure(&result, c, recognize.Default(), resolver)
+
+	loc := result.PhysicalLocation
+	if loc == nil || loc.URI != "foo/testdata/list.golden" || loc.StartLine != 1 {
+		t.Errorf("PhysicalLocation = %+v, want the golden file", loc)
+	}
+	if len(result.Fixes) != 1 {
+		t.Fatalf("Fixes = %+v, want one", result.Fixes)
+	}
+	change := result.Fixes[0].Changes[0]
+	want := sarif.Replacement{ByteLength: len(golden), InsertedText: "<ul>\n<li>b</li>\n</ul>\n"}
+	if change.URI != "foo/testdata/list.golden" || len(change.Replacements) != 1 || change.Replacements[0] != want {
+		t.Errorf("change = %+v, want the golden contents replaced", change)
+	}
+	assertion, _ := result.Properties["assertion"].(map[string]any)
+	if assertion["library"] != "golden" || assertion["golden"] != "testdata/list.golden" {
+		t.Errorf("assertion = %v", assertion)
+	}
+}
+
+func TestDescribeGoldenFailure_TestName(t *testing.T) {
+	resolver := writeTestModule(t, map[string]string{
+		"foo/render_test.go":               "package foo\n",
+		"foo/testdata/TestRender/a.golden": "x\n",
+	})
+	events := testOutput("TestRender/a",
+		"    render_test.go:30: Result did not match the golden fixture. Diff is below:\n",
+		"        --- Expected\n",
+		"        +++ Actual\n",
+		"        @@ -1 +1 @@\n",
+		"        -y\n",
+		"        +z\n",
+	)
+	c := collectTests(events)[0]
+
+	result := sarif.Result{Message: c.message()}
+	describeAssertionFailure(&result, c, recognize.Default(), resolver)
+
+	// The diff does not apply to the golden file, so only the location
+	// is set.
+	loc := result.PhysicalLocation
+	if loc == nil || loc.URI != "foo/testdata/TestRender/a.golden" {
+		t.Errorf("PhysicalLocation = %+v, want the golden file named after the test", loc)
+	}
+	if result.Fixes != nil {
+		t.Errorf("Fixes = %+v, want none", result.Fixes)
+	}
+}
+
+func TestGoldenActual(t *testing.T) {
+	hunks, _ := diff.ReadHunks(strings.Split("@@ -2 +2 @@\n-b\n+B", "\n"))
+
+	tests := []struct {
+		name    string
+		golden  string
+		printed string
+		hunks   []diff.Hunk
+		want    string
+		ok      bool
+	}{
+		{"printed", "a\n", "b\n", nil, "b\n", true},
+		{"applied", "a\nb\nc\n", "", hunks, "a\nB\nc\n", true},
+		{"no trailing newline", "a\nb", "", hunks, "a\nB", true},
+		{"mismatch", "a\nx\n", "", hunks, "", false},
+		{"no diff", "a\n", "", nil, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := goldenActual(tt.golden, tt.printed, tt.hunks)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("goldenActual() = %q, %v, want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// Package gotrace parses goroutine tracebacks printed by the Go runtime.
+package gotrace
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Frame is one call in a goroutine stack.
+type Frame struct {
+	// Function is the fully qualified function name, without arguments.
+	Function string
+	// File is the source file path as printed by the runtime.
+	File string
+	// Line is the 1-based line number in File.
+	Line int
+}
+
+// Goroutine is one goroutine of a traceback.
+type Goroutine struct {
+	// ID is the goroutine number.
+	ID int
+	// State is the scheduling state, such as "running" or "chan receive".
+	State string
+	// Frames are the stack frames, innermost first.
+	Frames []Frame
+	// CreatedBy is the go statement that started the goroutine, if printed.
+	CreatedBy *Frame
+}
+
+var (
+	headerRe   = regexp.MustCompile(`^goroutine (\d+)(?: gp=\S+ m=\S+(?: mp=\S+)?)? \[([^\]]*)\]:$`)
+	locationRe = regexp.MustCompile(`^\t(.+?):(\d+)(?: \+0x[0-9a-f]+)?(?: fp=\S+ sp=\S+ pc=\S+)?$`)
+)
+
+// Parse extracts the goroutines from traceback text. Lines outside
+// goroutine blocks are ignored.
+func Parse(text string) []Goroutine {
+	var (
+		goroutines []Goroutine
+		current    *Goroutine
+		pending    *Frame
+		created    bool
+	)
+
+	for line := range strings.Lines(text) {
+		line = strings.TrimRight(line, "\r\n")
+
+		if m := headerRe.FindStringSubmatch(line); m != nil {
+			id, _ := strconv.Atoi(m[1])
+			goroutines = append(goroutines, Goroutine{ID: id, State: m[2]})
+			current = &goroutines[len(goroutines)-1]
+			pending = nil
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		if m := locationRe.FindStringSubmatch(line); m != nil && pending != nil {
+			pending.File = m[1]
+			pending.Line, _ = strconv.Atoi(m[2])
+			if created {
+				current.CreatedBy = pending
+			} else {
+				current.Frames = append(current.Frames, *pending)
+			}
+			pending = nil
+			continue
+		}
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "\t"):
+			// A blank line ends the goroutine block.
+			if line == "" {
+				current = nil
+			}
+			pending = nil
+		case strings.HasPrefix(line, "created by "):
+			fn := strings.TrimPrefix(line, "created by ")
+			fn, _, _ = strings.Cut(fn, " in goroutine ")
+			pending = &Frame{Function: fn}
+			created = true
+		case strings.HasPrefix(line, "..."):
+			// "...additional frames elided..." and similar markers.
+			pending = nil
+		default:
+			pending = &Frame{Function: trimArgs(line)}
+			created = false
+		}
+	}
+
+	return goroutines
+}
+
+// trimArgs removes the trailing argument list from a traceback function
+// line, as in "pkg.(*T).Method(0xc000010000, {0x1, 0x2})".
+func trimArgs(line string) string {
+	if !strings.HasSuffix(line, ")") {
+		return line
+	}
+	depth := 0
+	for i := len(line) - 1; i >= 0; i-- {
+		switch line[i] {
+		case ')':
+			depth++
+		case '(':
+			depth--
+			if depth == 0 {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
+
+// SplitFunction splits a fully qualified function name into its package
+// import path and the package-local name, as in
+// "example.com/foo.(*T).Method" -> "example.com/foo", "(*T).Method".
+func SplitFunction(fn string) (pkg, name string) {
+	slash := strings.LastIndex(fn, "/")
+	dot := strings.Index(fn[slash+1:], ".")
+	if dot < 0 {
+		return "", fn
+	}
+	dot += slash + 1
+	return fn[:dot], fn[dot+1:]
+}
+
+// IsInit reports whether fn is a package initializer, such as
+// "example.com/foo.init" or "example.com/foo.init.0".
+func IsInit(fn string) bool {
+	_, name := SplitFunction(fn)
+	return name == "init" || (strings.HasPrefix(name, "init.") && isDigits(name[len("init."):]))
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
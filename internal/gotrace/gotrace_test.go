@@ -0,0 +1,116 @@
+package gotrace
+
+import "testing"
+
+const testTrace = `panic: boom [recovered]
+	panic: boom
+
+goroutine 7 [running]:
+testing.tRunner.func1.2({0x5b2f40, 0x6400b0})
+	/usr/local/go/src/testing/testing.go:1734 +0x21c
+example.com/foo.TestBar(0xc000003a40)
+	/src/foo/foo_test.go:12 +0x25
+testing.tRunner(0xc000003a40, 0x61e3a8)
+	/usr/local/go/src/testing/testing.go:1792 +0xf4
+created by testing.(*T).Run in goroutine 1
+	/usr/local/go/src/testing/testing.go:1851 +0x413
+
+goroutine 1 gp=0xc000002380 m=nil [chan receive]:
+testing.(*T).Run(0xc000003880, {0x5d6a0c?, 0x0?}, 0x61e3a8)
+	/usr/local/go/src/testing/testing.go:1859 +0x431
+...additional frames elided...
+exit status 2
+`
+
+func TestParse(t *testing.T) {
+	goroutines := Parse(testTrace)
+	if len(goroutines) != 2 {
+		t.Fatalf("got %d goroutines, want 2", len(goroutines))
+	}
+
+	g := goroutines[0]
+	if g.ID != 7 || g.State != "running" {
+		t.Errorf("goroutine = %d [%s], want 7 [running]", g.ID, g.State)
+	}
+	if len(g.Frames) != 3 {
+		t.Fatalf("got %d frames, want 3", len(g.Frames))
+	}
+	if g.Frames[1].Function != "example.com/foo.TestBar" {
+		t.Errorf("Frames[1].Function = %q", g.Frames[1].Function)
+	}
+	if g.Frames[1].File != "/src/foo/foo_test.go" || g.Frames[1].Line != 12 {
+		t.Errorf("Frames[1] location = %s:%d", g.Frames[1].File, g.Frames[1].Line)
+	}
+	if g.CreatedBy == nil || g.CreatedBy.Function != "testing.(*T).Run" || g.CreatedBy.Line != 1851 {
+		t.Errorf("CreatedBy = %+v, want testing.(*T).Run at line 1851", g.CreatedBy)
+	}
+
+	g = goroutines[1]
+	if g.ID != 1 || g.State != "chan receive" || len(g.Frames) != 1 {
+		t.Errorf("goroutine = %+v, want 1 [chan receive] with 1 frame", g)
+	}
+	if g.Frames[0].Function != "testing.(*T).Run" {
+		t.Errorf("Frames[0].Function = %q", g.Frames[0].Function)
+	}
+}
+
+func TestParse_FatalError(t *testing.T) {
+	trace := `fatal error: stack overflow
+
+runtime stack:
+runtime.throw({0x5579fd?, 0x7ffdbde88de0?})
+	/usr/local/go/src/runtime/panic.go:1243 +0x48 fp=0x7ffdbde88da8 sp=0x7ffdbde88d78 pc=0x4865c8
+
+goroutine 7 gp=0x3bcc60ae54a0 m=0 mp=0x6fd4e0 [running]:
+example.com/foo.rec(0x2aaaa51?)
+	/src/foo/foo_test.go:14 +0x2b fp=0x3bcc80be0398 sp=0x3bcc80be0390 pc=0x543b6b
+example.com/foo.rec(...)
+	/src/foo/foo_test.go:14
+`
+	goroutines := Parse(trace)
+	if len(goroutines) != 1 {
+		t.Fatalf("got %d goroutines, want 1", len(goroutines))
+	}
+	frames := goroutines[0].Frames
+	if len(frames) != 2 {
+		t.Fatalf("got %d frames, want 2", len(frames))
+	}
+	for _, f := range frames {
+		if f.Function != "example.com/foo.rec" || f.File != "/src/foo/foo_test.go" || f.Line != 14 {
+			t.Errorf("frame = %+v, want example.com/foo.rec at foo_test.go:14", f)
+		}
+	}
+}
+
+func TestSplitFunction(t *testing.T) {
+	tests := []struct {
+		fn, pkg, name string
+	}{
+		{"example.com/foo.TestBar", "example.com/foo", "TestBar"},
+		{"example.com/foo.(*T).Method", "example.com/foo", "(*T).Method"},
+		{"example.com/foo.v2/bar.init.0", "example.com/foo.v2/bar", "init.0"},
+		{"main.main", "main", "main"},
+	}
+	for _, tt := range tests {
+		pkg, name := SplitFunction(tt.fn)
+		if pkg != tt.pkg || name != tt.name {
+			t.Errorf("SplitFunction(%q) = %q, %q, want %q, %q", tt.fn, pkg, name, tt.pkg, tt.name)
+		}
+	}
+}
+
+func TestIsInit(t *testing.T) {
+	tests := map[string]bool{
+		"example.com/foo.init":        true,
+		"example.com/foo.init.0":      true,
+		"example.com/foo.init.func1":  false,
+		"example.com/foo.initialize":  false,
+		"example.com/init.TestBar":    false,
+		"example.com/foo.(*T).init.0": false,
+	}
+	for fn, want := range tests {
+		if got := IsInit(fn); got != want {
+			t.Errorf("IsInit(%q) = %v, want %v", fn, got, want)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/buildjson"
+	"github.com/ivuorinen/go-test-sarif-action/internal/compress"
+	"github.com/ivuorinen/go-test-sarif-action/internal/coverage"
+	"github.com/ivuorinen/go-test-sarif-action/internal/ginkgojson"
+	"github.com/ivuorinen/go-test-sarif-action/internal/testjson"
+	"github.com/ivuorinen/go-test-sarif-action/internal/vetjson"
+)
+
+// inputFormat is the kind of Go toolchain output held by an input.
+type inputFormat int
+
+const (
+	// formatTestJSON is go test -json or go tool test2json output.
+	formatTestJSON inputFormat = iota
+	// formatBuildJSON is go build -json output.
+	formatBuildJSON
+	// formatVetJSON is go vet -json output.
+	formatVetJSON
+	// formatGinkgoJSON is a Ginkgo v2 JSON report.
+	formatGinkgoJSON
+	// formatBazelTestlogs is the test.xml and test.log of a Bazel test
+	// target, converted to go test events.
+	formatBazelTestlogs
+	// formatCoverProfile is a go test -coverprofile coverage profile.
+	formatCoverProfile
+)
+
+// String returns the name of the command that produces the format.
+func (f inputFormat) String() string {
+	switch f {
+	case formatBuildJSON:
+		return "go build -json"
+	case formatVetJSON:
+		return "go vet -json"
+	case formatGinkgoJSON:
+		return "ginkgo --json-report"
+	case formatBazelTestlogs:
+		return "bazel-testlogs"
+	case formatCoverProfile:
+		return "go test -coverprofile"
+	}
+	return "go test -json"
+}
+
+// detectSize is the length of the prefix of an input that its format is
+// detected from.
+const detectSize = 1 << 20
+
+// detectFormat determines the format of an input from a prefix of its
+// content, whose last line may be cut short. A JSON array is a Ginkgo
+// report, a mode line starts a coverage profile and an object whose first
+// value is an object, keyed by package, comes from go vet. Event streams
+// whose events are all build events come from go build; other streams
+// come from go test. Unrecognized content is treated as test output, so
+// that parsing reports the error.
+func detectFormat(data []byte) inputFormat {
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	first := 0
+	for first < len(lines) {
+		line := bytes.TrimSpace(lines[first])
+		if len(line) != 0 && line[0] != '#' {
+			break
+		}
+		first++
+	}
+	if first == len(lines) {
+		return formatTestJSON
+	}
+
+	rest := bytes.TrimSpace(bytes.Join(lines[first:], nil))
+	switch {
+	case rest[0] == '[':
+		return formatGinkgoJSON
+	case bytes.HasPrefix(rest, []byte("mode: ")):
+		return formatCoverProfile
+	case isVetJSON(rest):
+		return formatVetJSON
+	}
+
+	events := 0
+	for i, raw := range lines[first:] {
+		line := bytes.TrimSpace(raw)
+		if len(line) == 0 {
+			continue
+		}
+		var probe struct {
+			Action string
+		}
+		if err := json.Unmarshal(line, &probe); err != nil {
+			if first+i == len(lines)-1 && !bytes.HasSuffix(raw, []byte("\n")) {
+				// The line was cut off by the end of the prefix.
+				break
+			}
+			return formatTestJSON
+		}
+		if !strings.HasPrefix(probe.Action, "build-") {
+			return formatTestJSON
+		}
+		events++
+	}
+	if events > 0 {
+		return formatBuildJSON
+	}
+	return formatTestJSON
+}
+
+// isVetJSON reports whether data starts with a go vet -json object: an
+// empty object or one whose first value, the findings of a package, is an
+// object itself.
+func isVetJSON(data []byte) bool {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return false
+	}
+	tok, err := dec.Token()
+	if err != nil {
+		return false
+	}
+	if tok == json.Delim('}') {
+		return true
+	}
+	if _, ok := tok.(string); !ok {
+		return false
+	}
+	tok, err = dec.Token()
+	return err == nil && tok == json.Delim('{')
+}
+
+// readInputs reads and parses the input files concurrently, detecting
+// the format of each from its content. A directory is read as a
+// bazel-testlogs tree, giving one input per test target. The first error
+// encountered, in input order, is returned prefixed with its file path.
+func readInputs(paths []string) ([]input, error) {
+	inputs := make([][]input, len(paths))
+	errs := make([]error, len(paths))
+
+	parallel(len(paths), func(i int) {
+		path := paths[i]
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			inputs[i], errs[i] = readBazelTestlogs(path)
+			return
+		}
+		in, err := readInput(path)
+		inputs[i], errs[i] = []input{in}, err
+	})
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", paths[i], err)
+		}
+	}
+	return slices.Concat(inputs...), nil
+}
+
+// parallel calls fn for each index below n, running at most GOMAXPROCS
+// calls at a time so that many inputs do not each hold an open file and
+// a detection buffer at once.
+func parallel(n int, fn func(i int)) {
+	sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+	var wg sync.WaitGroup
+	for i := range n {
+		sem <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			fn(i)
+		})
+	}
+	wg.Wait()
+}
+
+// readInput reads and parses a single input file, detecting its format
+// from a prefix. Gzip-compressed files are decompressed transparently and
+// the path "-" reads standard input. The input is parsed as it is read,
+// without buffering it whole.
+func readInput(path string) (input, error) {
+	rc, err := compress.Open(path)
+	if err != nil {
+		return input{}, err
+	}
+	defer func() { _ = rc.Close() }()
+
+	r := bufio.NewReaderSize(rc, detectSize)
+	prefix, err := r.Peek(detectSize)
+	if err != nil && err != io.EOF {
+		return input{}, err
+	}
+
+	in := input{Path: path, Format: detectFormat(prefix)}
+	switch in.Format {
+	case formatVetJSON:
+		in.Vet, err = vetjson.Parse(r)
+	case formatBuildJSON:
+		in.Build, err = buildjson.Parse(r)
+	case formatGinkgoJSON:
+		in.Ginkgo, err = ginkgojson.Parse(r)
+	case formatCoverProfile:
+		in.Coverage, err = coverage.Parse(r)
+	default:
+		in.Events, err = testjson.Parse(r)
+	}
+	switch {
+	case err != nil && in.Format != formatTestJSON:
+		return input{}, fmt.Errorf("%s output: %w", in.Format, err)
+	case err != nil:
+		return input{}, err
+	}
+	return in, nil
+}
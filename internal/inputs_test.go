@@ -0,0 +1,194 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/compress"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want inputFormat
+	}{
+		{"test events", `{"Action":"run","Package":"p","Test":"TestA"}` + "\n", formatTestJSON},
+		{
+			"test events after build failure",
+			`{"ImportPath":"p [p.test]","Action":"build-output","Output":"# p\n"}` + "\n" +
+				`{"ImportPath":"p [p.test]","Action":"build-fail"}` + "\n" +
+				`{"Action":"fail","Package":"p","FailedBuild":"p [p.test]"}` + "\n",
+			formatTestJSON,
+		},
+		{
+			"build events",
+			`{"ImportPath":"p","Action":"build-output","Output":"# p\n"}` + "\n" +
+				`{"ImportPath":"p","Action":"build-fail"}` + "\n",
+			formatBuildJSON,
+		},
+		{"indented vet", "{\n\t\"p\": {}\n}\n", formatVetJSON},
+		{"compact vet", `{"p": {"printf": []}}` + "\n", formatVetJSON},
+		{"vet with comments", "# p\n{}\n", formatVetJSON},
+		{"indented ginkgo", "[\n  {\n    \"SuitePath\": \"/src\"\n  }\n]\n", formatGinkgoJSON},
+		{"compact ginkgo", `[{"SuitePath":"/src","SpecReports":[]}]`, formatGinkgoJSON},
+		{"coverage profile", "mode: set\nexample.com/app/foo/x.go:3.14,5.2 1 1\n", formatCoverProfile},
+		{"corrupt first event", `{"Action":"run","Package":"p",` + "\n" + `{"Action":"pass","Package":"p"}` + "\n", formatTestJSON},
+		{"corrupt object", "{broken\n", formatTestJSON},
+		{
+			"build events cut short",
+			`{"ImportPath":"p","Action":"build-output","Output":"# p\n"}` + "\n" + `{"ImportPath":"p","Act`,
+			formatBuildJSON,
+		},
+		{"empty", "", formatTestJSON},
+		{"garbage", "not json\n", formatTestJSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectFormat([]byte(tt.data)); got != tt.want {
+				t.Errorf("detectFormat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadInput_CorruptFirstEvent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.json")
+	data := `{"Action":"run","Package":"p",` + "\n" + `{"Action":"pass","Package":"p"}` + "\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	_, err := readInput(path)
+	if err == nil || !strings.Contains(err.Error(), "line 1") || strings.Contains(err.Error(), "vet") {
+		t.Errorf("error = %v, want a test JSON error naming line 1", err)
+	}
+}
+
+func TestReadInput_LargerThanPrefix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.json")
+	event := `{"Action":"output","Package":"p","Test":"TestA","Output":"` + strings.Repeat("x", 1000) + `\n"}` + "\n"
+	n := detectSize/len(event) + 10
+	if err := os.WriteFile(path, []byte(strings.Repeat(event, n)), 0o600); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	in, err := readInput(path)
+	if err != nil {
+		t.Fatalf("readInput returned error: %v", err)
+	}
+	if in.Format != formatTestJSON || len(in.Events) != n {
+		t.Errorf("read %d events as %v, want %d test events", len(in.Events), in.Format, n)
+	}
+}
+
+func TestReadInputs(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"test.json":  `{"Action":"pass","Package":"p"}` + "\n",
+		"build.json": `{"ImportPath":"p","Action":"build-fail"}` + "\n",
+		"vet.json":   `{"p": {}}` + "\n",
+	}
+	var paths []string
+	for _, name := range []string{"test.json", "build.json", "vet.json"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(files[name]), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		paths = append(paths, path)
+	}
+
+	inputs, err := readInputs(paths)
+	if err != nil {
+		t.Fatalf("readInputs returned error: %v", err)
+	}
+	if in := inputs[0]; in.Format != formatTestJSON || len(in.Events) != 1 {
+		t.Errorf("test input = %+v", in)
+	}
+	if in := inputs[1]; in.Format != formatBuildJSON || len(in.Build) != 1 {
+		t.Errorf("build input = %+v", in)
+	}
+	if in := inputs[2]; in.Format != formatVetJSON || len(in.Vet) != 1 {
+		t.Errorf("vet input = %+v", in)
+	}
+}
+
+func TestReadInputs_Many(t *testing.T) {
+	dir := t.TempDir()
+	paths := make([]string, 1000)
+	for i := range paths {
+		paths[i] = filepath.Join(dir, fmt.Sprintf("shard%d.json", i))
+		event := fmt.Sprintf(`{"Action":"pass","Package":"p%d"}`, i) + "\n"
+		if err := os.WriteFile(paths[i], []byte(event), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", paths[i], err)
+		}
+	}
+
+	inputs, err := readInputs(paths)
+	if err != nil {
+		t.Fatalf("readInputs returned error: %v", err)
+	}
+	for i, in := range inputs {
+		if in.Path != paths[i] || len(in.Events) != 1 || in.Events[0].Package != fmt.Sprintf("p%d", i) {
+			t.Fatalf("inputs[%d] = %+v, want the events of %s", i, in, paths[i])
+		}
+	}
+}
+
+func TestParallel_Limit(t *testing.T) {
+	var running, peak atomic.Int32
+	var calls [100]atomic.Bool
+	parallel(len(calls), func(i int) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		calls[i].Store(true)
+		running.Add(-1)
+	})
+
+	for i := range calls {
+		if !calls[i].Load() {
+			t.Errorf("fn(%d) was not called", i)
+		}
+	}
+	if limit := int32(runtime.GOMAXPROCS(0)); peak.Load() > limit {
+		t.Errorf("%d calls ran at once, want at most %d", peak.Load(), limit)
+	}
+}
+
+func TestReadInputs_Gzip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.json.gz")
+	data, err := compress.Gzip([]byte(`{"Action":"fail","Package":"p","Test":"TestBar"}` + "\n"))
+	if err != nil {
+		t.Fatalf("failed to compress input: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+
+	inputs, err := readInputs([]string{path})
+	if err != nil {
+		t.Fatalf("readInputs returned error: %v", err)
+	}
+	if len(inputs) != 1 || len(inputs[0].Events) != 1 || inputs[0].Events[0].Test != "TestBar" {
+		t.Errorf("inputs = %+v, want one TestBar event", inputs)
+	}
+}
+
+func TestReadInputs_ErrorNamesFile(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.json")
+	_, err := readInputs([]string{missing})
+	if err == nil || !strings.Contains(err.Error(), missing) {
+		t.Errorf("error = %v, want one naming %s", err, missing)
+	}
+}
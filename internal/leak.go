@@ -0,0 +1,106 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/gotrace"
+	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
+	"github.com/ivuorinen/go-test-sarif-action/internal/source"
+)
+
+// leakRule is reported for goroutines leaked by tests, as detected by
+// go.uber.org/goleak.
+var leakRule = sarif.Rule{
+	ID:          "go-goroutine-leak",
+	Description: "goroutine leaked by tests",
+}
+
+// leakMarker starts the goroutine list printed by goleak.
+const leakMarker = "found unexpected goroutines:"
+
+// goleakHeaderRe matches the line goleak prints before each goroutine,
+// "[Goroutine 7 in state chan receive, with pkg.f on top of the stack:".
+var goleakHeaderRe = regexp.MustCompile(`^\[?\s*Goroutine (\d+) in state ([^,]+), with .+ on top of the stack:$`)
+
+// findGoroutineLeaks returns the goroutines reported as leaked by goleak
+// in output.
+func findGoroutineLeaks(output string) ([]gotrace.Goroutine, bool) {
+	_, after, ok := strings.Cut(output, leakMarker)
+	if !ok {
+		return nil, false
+	}
+
+	// Drop the indentation added to test log output and rewrite goleak's
+	// headers as runtime headers, which older goleak versions omit.
+	var b strings.Builder
+	for line := range strings.Lines(after) {
+		line = strings.TrimLeft(strings.TrimRight(line, "\r\n"), " ")
+		if m := goleakHeaderRe.FindStringSubmatch(line); m != nil {
+			line = fmt.Sprintf("goroutine %s [%s]:", m[1], m[2])
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+
+	var leaks []gotrace.Goroutine
+	for _, g := range gotrace.Parse(b.String()) {
+		// Newer goleak versions print both headers, leaving the
+		// rewritten one empty.
+		if len(g.Frames) > 0 || g.CreatedBy != nil {
+			leaks = append(leaks, g)
+		}
+	}
+	return leaks, len(leaks) > 0
+}
+
+// leakResults returns one result per goroutine leaked by the package of
+// pkg, or by test if it is not empty. Each result is located where the
+// goroutine was created.
+func leakResults(leaks []gotrace.Goroutine, pkg, test string, resolver *source.Resolver) []sarif.Result {
+	results := make([]sarif.Result, 0, len(leaks))
+	for _, g := range leaks {
+		by := "tests of " + pkg
+		if test != "" {
+			by = test
+		}
+		result := sarif.Result{
+			RuleID:   leakRule.ID,
+			Level:    "error",
+			Message:  fmt.Sprintf("goroutine %d [%s] leaked by %s", g.ID, g.State, by),
+			Location: &sarif.LogicalLocation{Module: pkg, Function: test},
+			Properties: map[string]any{
+				"goroutine": g.ID,
+				"state":     g.State,
+			},
+			Stacks: goroutineStacks([]gotrace.Goroutine{g}, resolver),
+		}
+		if len(g.Frames) > 0 {
+			result.Properties["topFunction"] = g.Frames[0].Function
+		}
+		if test != "" {
+			result.Properties["test"] = test
+		}
+
+		if g.CreatedBy != nil {
+			result.Message += ", created by " + g.CreatedBy.Function
+			result.Properties["createdBy"] = g.CreatedBy.Function
+		}
+		if frame, ok := leakSite(g, pkg); ok {
+			result.PhysicalLocation = &sarif.PhysicalLocation{URI: resolver.URI(frame.File), StartLine: frame.Line}
+		}
+
+		results = append(results, result)
+	}
+	return results
+}
+
+// leakSite returns the go statement that created g, falling back to its
+// innermost frame in pkg.
+func leakSite(g gotrace.Goroutine, pkg string) (gotrace.Frame, bool) {
+	if g.CreatedBy != nil && g.CreatedBy.File != "" {
+		return *g.CreatedBy, true
+	}
+	return frameInPackage(g, pkg)
+}
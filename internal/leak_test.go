@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/testjson"
+)
+
+// goleakOutput is the output of goleak.VerifyTestMain for two leaked
+// goroutines, in the format of goleak versions that repeat the runtime
+// header.
+const goleakOutput = "PASS\n" +
+	"goleak: Errors on successful test run: found unexpected goroutines:\n" +
+	"[Goroutine 7 in state chan receive, with example.com/app/foo.leak.func1 on top of the stack:\n" +
+	"goroutine 7 [chan receive]:\n" +
+	"example.com/app/foo.leak.func1()\n" +
+	"\t/src/app/foo/foo.go:10 +0x25\n" +
+	"created by example.com/app/foo.leak in goroutine 6\n" +
+	"\t/src/app/foo/foo.go:9 +0x6f\n" +
+	" Goroutine 8 in state select, with example.com/app/foo.poll on top of the stack:\n" +
+	"goroutine 8 [select]:\n" +
+	"example.com/app/foo.poll()\n" +
+	"\t/src/app/foo/poll.go:20 +0x85\n" +
+	"created by example.com/app/foo.Start in goroutine 6\n" +
+	"\t/src/app/foo/poll.go:14 +0x1c\n" +
+	"]\n" +
+	"FAIL\texample.com/app/foo\t0.012s\n"
+
+func TestFindGoroutineLeaks(t *testing.T) {
+	leaks, ok := findGoroutineLeaks(goleakOutput)
+	if !ok || len(leaks) != 2 {
+		t.Fatalf("findGoroutineLeaks() = %+v, %v, want 2 goroutines", leaks, ok)
+	}
+	g := leaks[0]
+	if g.ID != 7 || g.State != "chan receive" || len(g.Frames) != 1 {
+		t.Errorf("goroutine = %+v, want goroutine 7 with one frame", g)
+	}
+	if g.CreatedBy == nil || g.CreatedBy.Function != "example.com/app/foo.leak" || g.CreatedBy.Line != 9 {
+		t.Errorf("CreatedBy = %+v, want example.com/app/foo.leak at line 9", g.CreatedBy)
+	}
+	if leaks[1].ID != 8 || leaks[1].State != "select" {
+		t.Errorf("goroutine = %+v, want goroutine 8 [select]", leaks[1])
+	}
+}
+
+func TestFindGoroutineLeaks_TestLog(t *testing.T) {
+	// goleak.VerifyNone reports through t.Error, without the runtime
+	// header in older versions.
+	output := "foo_test.go:12: found unexpected goroutines:\n" +
+		"    [Goroutine 21 in state sleep, with time.Sleep on top of the stack:\n" +
+		"    time.Sleep(0x3b9aca00)\n" +
+		"    \t/usr/local/go/src/runtime/time.go:300 +0xf2\n" +
+		"    example.com/app/foo.TestFoo.func1()\n" +
+		"    \t/src/app/foo/foo_test.go:10 +0x1d\n" +
+		"    created by example.com/app/foo.TestFoo in goroutine 20\n" +
+		"    \t/src/app/foo/foo_test.go:9 +0x3c\n" +
+		"    ]\n"
+
+	leaks, ok := findGoroutineLeaks(output)
+	if !ok || len(leaks) != 1 {
+		t.Fatalf("findGoroutineLeaks() = %+v, %v, want 1 goroutine", leaks, ok)
+	}
+	if g := leaks[0]; g.ID != 21 || g.State != "sleep" || len(g.Frames) != 2 || g.CreatedBy == nil {
+		t.Errorf("goroutine = %+v, want goroutine 21 with two frames", g)
+	}
+}
+
+func TestFindGoroutineLeaks_None(t *testing.T) {
+	if _, ok := findGoroutineLeaks("PASS\nexit status 1\n"); ok {
+		t.Error("findGoroutineLeaks() reported leaks without goleak output")
+	}
+}
+
+func TestLeakResults(t *testing.T) {
+	resolver := writeTestModule(t, map[string]string{})
+	leaks, _ := findGoroutineLeaks(goleakOutput)
+
+	results := leakResults(leaks, testPkg, "", resolver)
+	if len(results) != 2 {
+		t.Fatalf("leakResults() returned %d results, want 2", len(results))
+	}
+	r := results[0]
+	if r.RuleID != leakRule.ID || !strings.HasPrefix(r.Message, "goroutine 7 [chan receive] leaked") {
+		t.Errorf("result = %s: %q, want a leak of goroutine 7", r.RuleID, r.Message)
+	}
+	if loc := r.PhysicalLocation; loc == nil || loc.URI != "file:///src/app/foo/foo.go" || loc.StartLine != 9 {
+		t.Errorf("PhysicalLocation = %+v, want the created by frame", loc)
+	}
+	if len(r.Stacks) != 1 || len(r.Stacks[0].Frames) != 2 {
+		t.Errorf("Stacks = %+v, want one stack with the created by frame", r.Stacks)
+	}
+	if r.Properties["createdBy"] != "example.com/app/foo.leak" || r.Properties["state"] != "chan receive" {
+		t.Errorf("Properties = %v", r.Properties)
+	}
+}
+
+func TestBuildReport_GoroutineLeak(t *testing.T) {
+	events := []testjson.TestEvent{
+		{Action: "run", Package: testPkg, Test: "TestFoo"},
+		{Action: "pass", Package: testPkg, Test: "TestFoo"},
+	}
+	events = append(events, pkgOutput(strings.SplitAfter(goleakOutput, "\n")...)...)
+	events = append(events, testjson.TestEvent{Action: "fail", Package: testPkg})
+
+	report := buildReport([]input{{Path: "in.json", Events: events}}, ConvertOptions{SourceRoot: t.TempDir()})
+
+	if len(report.Results) != 2 {
+		t.Fatalf("got %d results, want one per leaked goroutine: %+v", len(report.Results), report.Results)
+	}
+	for _, r := range report.Results {
+		if r.RuleID != leakRule.ID {
+			t.Errorf("RuleID = %q, want %q", r.RuleID, leakRule.ID)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/compress"
+)
+
+// testBinarySuffix is the file name suffix of binaries built by go test -c.
+const testBinarySuffix = ".test"
+
+// packageFor returns the package to assign to events of the input at path
+// that lack a Package field: an explicit per-input mapping wins over the
+// default package, which wins over a name inferred from the file name.
+func packageFor(path string, opts ConvertOptions) string {
+	if pkg, ok := opts.PackageByInput[path]; ok {
+		return pkg
+	}
+	if pkg, ok := opts.PackageByInput[filepath.Base(path)]; ok {
+		return pkg
+	}
+	if opts.Package != "" {
+		return opts.Package
+	}
+	return packageFromBinaryName(path)
+}
+
+// packageFromBinaryName infers a package name from an input named after the
+// test binary that produced it, as in
+//
+//	go tool test2json -t ./foo.test -test.v > foo.test.json
+//
+// The extension of the output, if any, and of a compression format are
+// ignored. It returns an empty string when the name does not follow that
+// pattern.
+func packageFromBinaryName(path string) string {
+	name := compress.TrimExt(filepath.Base(path))
+	if pkg, ok := strings.CutSuffix(name, testBinarySuffix); ok {
+		return pkg
+	}
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	if pkg, ok := strings.CutSuffix(name, testBinarySuffix); ok {
+		return pkg
+	}
+	return ""
+}
+
+// fillPackage sets the package of events read from raw test2json output,
+// which carry no Package field. Build events, identified by their
+// ImportPath, are left alone.
+func fillPackage(in *input, pkg string) {
+	if pkg == "" {
+		return
+	}
+	for i := range in.Events {
+		if in.Events[i].Package == "" && in.Events[i].ImportPath == "" {
+			in.Events[i].Package = pkg
+		}
+	}
+}
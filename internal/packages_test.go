@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/testjson"
+)
+
+func TestPackageFor(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		opts ConvertOptions
+		want string
+	}{
+		{
+			name: "no information",
+			path: "results.json",
+			want: "",
+		},
+		{
+			name: "inferred from binary name",
+			path: "logs/foo.test.json.gz",
+			want: "foo",
+		},
+		{
+			name: "inferred from compressed raw output",
+			path: "logs/foo.test.zst",
+			want: "foo",
+		},
+		{
+			name: "default package",
+			path: "logs/foo.test.json",
+			opts: ConvertOptions{Package: "example.com/bar"},
+			want: "example.com/bar",
+		},
+		{
+			name: "mapping by path",
+			path: "logs/foo.test.json",
+			opts: ConvertOptions{
+				Package:        "example.com/bar",
+				PackageByInput: map[string]string{"logs/foo.test.json": "example.com/foo"},
+			},
+			want: "example.com/foo",
+		},
+		{
+			name: "mapping by file name",
+			path: "logs/foo.test.json",
+			opts: ConvertOptions{
+				PackageByInput: map[string]string{"foo.test.json": "example.com/foo"},
+			},
+			want: "example.com/foo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := packageFor(tt.path, tt.opts); got != tt.want {
+				t.Errorf("packageFor(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFillPackage(t *testing.T) {
+	in := input{Events: []testjson.TestEvent{
+		{Action: "fail", Test: "TestFoo"},
+		{Action: "fail", Package: "example.com/other", Test: "TestBar"},
+	}}
+
+	fillPackage(&in, "example.com/foo")
+
+	if got := in.Events[0].Package; got != "example.com/foo" {
+		t.Errorf("Events[0].Package = %q, want %q", got, "example.com/foo")
+	}
+	if got := in.Events[1].Package; got != "example.com/other" {
+		t.Errorf("Events[1].Package = %q, want %q", got, "example.com/other")
+	}
+}
@@ -0,0 +1,173 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/gotrace"
+	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
+	"github.com/ivuorinen/go-test-sarif-action/internal/source"
+)
+
+// Package failure kinds, recorded in the failureKind result property.
+const (
+	failureBuild       = "build-failed"
+	failureTests       = "test-failures"
+	failureTestExit    = "test-exit"
+	failureInitPanic   = "init-panic"
+	failurePanic       = "panic"
+	failureNoTestFiles = "no-test-files"
+	failureTestMain    = "testmain"
+	failureExitStatus  = "exit-status"
+	failureUnknown     = "unknown"
+)
+
+var exitStatusRe = regexp.MustCompile(`(?m)^exit status (\d+)$`)
+
+// packageFailure describes why a package failed.
+type packageFailure struct {
+	// Kind classifies the cause, as one of the failure* constants.
+	Kind string
+	// Summary is a one-line explanation of the cause.
+	Summary string
+	// ExitCode is the exit status of the test binary, or -1 if unknown.
+	ExitCode int
+	// Test is the test that was running when the binary exited, if any.
+	Test string
+	// Position is the source location responsible for the failure, if known.
+	Position *source.Position
+}
+
+// classifyPackageFailure determines why the package of pkg failed, using
+// the package-level output and the other test cases of the same input.
+func classifyPackageFailure(pkg *testCase, cases []*testCase, resolver *source.Resolver) packageFailure {
+	output := strings.Join(pkg.Output, "")
+	f := packageFailure{Kind: failureUnknown, ExitCode: -1}
+	if m := exitStatusRe.FindStringSubmatch(output); m != nil {
+		f.ExitCode, _ = strconv.Atoi(m[1])
+	}
+
+	var failed int
+	var unfinished *testCase
+	for _, c := range cases {
+		if c.pkg != pkg.pkg || c.test == "" {
+			continue
+		}
+		switch {
+		case c.Action == "fail":
+			failed++
+		case !c.done():
+			// Keep the innermost test: subtests start after their parents.
+			unfinished = c
+		}
+	}
+
+	switch {
+	case pkg.FailedBuild:
+		f.Kind = failureBuild
+		f.Summary = "package failed to build"
+	case failed > 0:
+		f.Kind = failureTests
+		f.Summary = fmt.Sprintf("%d test(s) failed", failed)
+	case unfinished != nil:
+		f.Kind = failureTestExit
+		f.Test = unfinished.test
+		f.Summary = fmt.Sprintf("test binary exited while %s was running (os.Exit or crash)", unfinished.test)
+		f.Position = findTestFunc(resolver, pkg.pkg, unfinished.test)
+	case strings.Contains(output, "panic: "):
+		f.Kind = failurePanic
+		f.Summary = "test binary panicked outside of a test"
+		if frame, ok := panicFrame(output, pkg.pkg); ok {
+			if gotrace.IsInit(frame.Function) {
+				f.Kind = failureInitPanic
+				f.Summary = "package initialization panicked"
+			}
+			f.Position = &source.Position{File: frame.File, Line: frame.Line}
+		}
+	case strings.Contains(output, "[no test files]"):
+		f.Kind = failureNoTestFiles
+		f.Summary = "package has no test files"
+	case sawFrame(pkg, "PASS"):
+		f.Kind = failureExitStatus
+		f.Summary = "test binary exited with a failure status after all tests passed"
+		if pos, ok := resolver.FindFunc(pkg.pkg, "TestMain", true); ok {
+			f.Kind = failureTestMain
+			f.Summary = "TestMain exited with a failure status after all tests passed"
+			f.Position = &pos
+		}
+	case f.ExitCode >= 0:
+		f.Kind = failureExitStatus
+		f.Summary = fmt.Sprintf("test binary exited with status %d", f.ExitCode)
+	}
+
+	return f
+}
+
+// describePackageFailure fills in a package-level result from its
+// classified failure and the package output.
+func describePackageFailure(result *sarif.Result, f packageFailure, output string, resolver *source.Resolver) {
+	switch {
+	case f.Summary != "" && output != "":
+		result.Message = f.Summary + "\n\n" + output
+	case f.Summary != "":
+		result.Message = f.Summary
+	}
+	if result.Properties == nil {
+		result.Properties = map[string]any{}
+	}
+	result.Properties["failureKind"] = f.Kind
+	if f.ExitCode >= 0 {
+		result.Properties["exitCode"] = f.ExitCode
+	}
+	if f.Test != "" {
+		result.Properties["test"] = f.Test
+	}
+	if f.Position != nil {
+		result.PhysicalLocation = &sarif.PhysicalLocation{
+			URI:         resolver.URI(f.Position.File),
+			StartLine:   f.Position.Line,
+			StartColumn: f.Position.Column,
+		}
+	}
+}
+
+// panicFrame returns the innermost frame of the panicking goroutine that
+// belongs to pkg, falling back to its innermost non-runtime frame.
+func panicFrame(output, pkg string) (gotrace.Frame, bool) {
+	goroutines := gotrace.Parse(output)
+	if len(goroutines) == 0 {
+		return gotrace.Frame{}, false
+	}
+
+	if frame, ok := frameInPackage(goroutines[0], pkg); ok {
+		return frame, true
+	}
+	for _, frame := range goroutines[0].Frames {
+		if fpkg, _ := gotrace.SplitFunction(frame.Function); fpkg != "runtime" {
+			return frame, true
+		}
+	}
+	return gotrace.Frame{}, false
+}
+
+// findTestFunc locates the top-level test function of test, which may
+// name a subtest.
+func findTestFunc(resolver *source.Resolver, pkg, test string) *source.Position {
+	name, _, _ := strings.Cut(test, "/")
+	if pos, ok := resolver.FindFunc(pkg, name, true); ok {
+		return &pos
+	}
+	return nil
+}
+
+// sawFrame reports whether the package printed the framing line.
+func sawFrame(c *testCase, line string) bool {
+	for _, frame := range c.Frames {
+		if strings.TrimSpace(frame) == line {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,178 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
+	"github.com/ivuorinen/go-test-sarif-action/internal/source"
+	"github.com/ivuorinen/go-test-sarif-action/internal/testjson"
+)
+
+const testPkg = "example.com/app/foo"
+
+// writeTestModule creates a module example.com/app with the given files
+// and returns a resolver for it.
+func writeTestModule(t *testing.T, files map[string]string) *source.Resolver {
+	t.Helper()
+	root := t.TempDir()
+	files["go.mod"] = "module example.com/app\n\ngo 1.25\n"
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	return source.NewResolver(root)
+}
+
+// pkgOutput returns package-level output events for lines.
+func pkgOutput(lines ...string) []testjson.TestEvent {
+	var events []testjson.TestEvent
+	for _, line := range lines {
+		events = append(events, testjson.TestEvent{Action: "output", Package: testPkg, Output: line})
+	}
+	return events
+}
+
+func classify(t *testing.T, events []testjson.TestEvent, resolver *source.Resolver) packageFailure {
+	t.Helper()
+	events = append(events, testjson.TestEvent{Action: "fail", Package: testPkg})
+	cases := collectTests(events)
+	for _, c := range cases {
+		if c.pkg == testPkg && c.test == "" {
+			return classifyPackageFailure(c, cases, resolver)
+		}
+	}
+	t.Fatal("no package case")
+	return packageFailure{}
+}
+
+func TestClassifyPackageFailure_TestMain(t *testing.T) {
+	resolver := writeTestModule(t, map[string]string{
+		"foo/foo_test.go": "package foo\n\nimport \"testing\"\n\nfunc TestMain(m *testing.M) { m.Run() }\n",
+	})
+	events := []testjson.TestEvent{
+		{Action: "run", Package: testPkg, Test: "TestOK"},
+		{Action: "pass", Package: testPkg, Test: "TestOK"},
+	}
+	events = append(events, pkgOutput("PASS\n", "FAIL\texample.com/app/foo\t0.003s\n")...)
+
+	f := classify(t, events, resolver)
+	if f.Kind != failureTestMain {
+		t.Errorf("Kind = %q, want %q", f.Kind, failureTestMain)
+	}
+	if f.Position == nil || f.Position.Line != 5 {
+		t.Errorf("Position = %+v, want TestMain at line 5", f.Position)
+	}
+}
+
+func TestClassifyPackageFailure_InitPanic(t *testing.T) {
+	events := pkgOutput(
+		"panic: assignment to entry in nil map\n",
+		"\n",
+		"goroutine 1 [running]:\n",
+		"example.com/app/foo.init.0()\n",
+		"\t/src/app/foo/foo.go:5 +0x28\n",
+		"exit status 2\n",
+	)
+
+	f := classify(t, events, source.NewResolver(""))
+	if f.Kind != failureInitPanic {
+		t.Errorf("Kind = %q, want %q", f.Kind, failureInitPanic)
+	}
+	if f.ExitCode != 2 {
+		t.Errorf("ExitCode = %d, want 2", f.ExitCode)
+	}
+	if f.Position == nil || f.Position.File != "/src/app/foo/foo.go" || f.Position.Line != 5 {
+		t.Errorf("Position = %+v, want foo.go:5", f.Position)
+	}
+}
+
+func TestClassifyPackageFailure_TestExit(t *testing.T) {
+	resolver := writeTestModule(t, map[string]string{
+		"foo/foo_test.go": "package foo\n\nimport \"testing\"\n\nfunc TestExit(t *testing.T) {}\n",
+	})
+	events := []testjson.TestEvent{
+		{Action: "run", Package: testPkg, Test: "TestExit"},
+		{Action: "run", Package: testPkg, Test: "TestExit/sub"},
+	}
+
+	f := classify(t, events, resolver)
+	if f.Kind != failureTestExit || f.Test != "TestExit/sub" {
+		t.Errorf("failure = %s (%s), want %s (TestExit/sub)", f.Kind, f.Test, failureTestExit)
+	}
+	if f.Position == nil || f.Position.Line != 5 {
+		t.Errorf("Position = %+v, want TestExit at line 5", f.Position)
+	}
+}
+
+func TestClassifyPackageFailure_Other(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []testjson.TestEvent
+		want   string
+	}{
+		{
+			name: "failed tests",
+			events: []testjson.TestEvent{
+				{Action: "run", Package: testPkg, Test: "TestBar"},
+				{Action: "fail", Package: testPkg, Test: "TestBar"},
+			},
+			want: failureTests,
+		},
+		{
+			name:   "build failure",
+			events: []testjson.TestEvent{{Action: "fail", Package: testPkg, FailedBuild: true}},
+			want:   failureBuild,
+		},
+		{
+			name:   "no test files",
+			events: pkgOutput("?   \texample.com/app/foo\t[no test files]\n"),
+			want:   failureNoTestFiles,
+		},
+		{
+			name:   "exit status",
+			events: pkgOutput("exit status 3\n"),
+			want:   failureExitStatus,
+		},
+		{
+			name: "unknown",
+			want: failureUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if f := classify(t, tt.events, source.NewResolver("")); f.Kind != tt.want {
+				t.Errorf("Kind = %q, want %q", f.Kind, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribePackageFailure(t *testing.T) {
+	result := sarif.Result{Message: "package example.com/app/foo failed"}
+	f := packageFailure{
+		Kind:     failureExitStatus,
+		Summary:  "test binary exited with status 3",
+		ExitCode: 3,
+		Position: &source.Position{File: "foo/foo.go", Line: 7},
+	}
+
+	describePackageFailure(&result, f, "exit status 3", source.NewResolver(""))
+
+	if result.Message != "test binary exited with status 3\n\nexit status 3" {
+		t.Errorf("Message = %q", result.Message)
+	}
+	if result.Properties["failureKind"] != failureExitStatus || result.Properties["exitCode"] != 3 {
+		t.Errorf("Properties = %v", result.Properties)
+	}
+	if result.PhysicalLocation == nil || result.PhysicalLocation.URI != "foo/foo.go" || result.PhysicalLocation.StartLine != 7 {
+		t.Errorf("PhysicalLocation = %+v", result.PhysicalLocation)
+	}
+}
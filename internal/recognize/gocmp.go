@@ -0,0 +1,51 @@
+package recognize
+
+import (
+	"regexp"
+	"strings"
+)
+
+// GoCmp recognizes github.com/google/go-cmp diffs, reported by convention
+// as "Foo() mismatch (-want +got):" followed by the diff.
+type GoCmp struct{}
+
+var gocmpHeaderRe = regexp.MustCompile(`\((-want \+got|-got \+want|-expected \+actual|-actual \+expected)\):?\s*$`)
+
+// Name implements Recognizer.
+func (GoCmp) Name() string { return "go-cmp" }
+
+// Recognize implements Recognizer.
+func (GoCmp) Recognize(output string) (Failure, bool) {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	for i, line := range lines {
+		m := gocmpHeaderRe.FindStringSubmatchIndex(line)
+		if m == nil {
+			continue
+		}
+
+		var f Failure
+		header := line
+		if file, n, msg, ok := splitLocation(line); ok {
+			f.File, f.Line = file, n
+			header = msg
+			m = gocmpHeaderRe.FindStringSubmatchIndex(header)
+		}
+		f.Title = strings.TrimSpace(header[:m[0]])
+		if f.Title == "" {
+			f.Title = "mismatch"
+		}
+		f.Title += " (" + header[m[2]:m[3]] + ")"
+
+		// The diff runs until the next log line.
+		end := len(lines)
+		for j := i + 1; j < len(lines); j++ {
+			if _, _, _, ok := splitLocation(lines[j]); ok {
+				end = j
+				break
+			}
+		}
+		f.Diff = dedent(lines[i+1 : end])
+		return f, true
+	}
+	return Failure{}, false
+}
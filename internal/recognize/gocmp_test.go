@@ -0,0 +1,48 @@
+package recognize
+
+import "testing"
+
+func TestGoCmp(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   Failure
+		ok     bool
+	}{
+		{
+			name: "want got",
+			output: "foo_test.go:15: Parse() mismatch (-want +got):\n" +
+				"      main.Config{\n" +
+				"    - \tName: \"a\",\n" +
+				"    + \tName: \"b\",\n" +
+				"      }\n" +
+				"foo_test.go:16: done\n",
+			want: Failure{
+				Title: "Parse() mismatch (-want +got)",
+				File:  "foo_test.go",
+				Line:  15,
+				Diff:  "  main.Config{\n- \tName: \"a\",\n+ \tName: \"b\",\n  }",
+			},
+			ok: true,
+		},
+		{
+			name:   "bare header",
+			output: "(-got +want):\n  -1\n  +2\n",
+			want:   Failure{Title: "mismatch (-got +want)", Diff: "-1\n+2"},
+			ok:     true,
+		},
+		{
+			name:   "plain log",
+			output: "foo_test.go:12: boom\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := GoCmp{}.Recognize(tt.output)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("Recognize() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package recognize
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/diff"
+)
+
+// GoldenPattern describes how a golden-file helper reports a mismatch.
+type GoldenPattern struct {
+	// Mismatch matches the output line reporting a mismatch. Its "file"
+	// submatch, if any, is the path of the golden file.
+	Mismatch *regexp.Regexp
+	// File is the path of the golden file when Mismatch does not capture
+	// it, with {test} standing for the test name.
+	File string
+}
+
+// GoldenPatterns returns the patterns of gotest.tools/v3/golden and of
+// github.com/sebdah/goldie, which by default names fixtures after the
+// test.
+func GoldenPatterns() []GoldenPattern {
+	return []GoldenPattern{
+		{Mismatch: regexp.MustCompile(`automatically update (?P<file>\S+) to the new expected value`)},
+		{
+			Mismatch: regexp.MustCompile(`Result did not match the golden fixture`),
+			File:     "testdata/{test}.golden",
+		},
+	}
+}
+
+// Golden recognizes golden-file mismatches and the unified diff printed
+// with them. Paths are relative to the package directory.
+type Golden struct {
+	// Patterns describe the helpers to recognize.
+	Patterns []GoldenPattern
+}
+
+// Name implements Recognizer.
+func (Golden) Name() string { return "golden" }
+
+// Recognize implements Recognizer.
+func (g Golden) Recognize(output string) (Failure, bool) {
+	var lines []string
+	for line := range strings.Lines(output) {
+		lines = append(lines, strings.TrimRight(line, "\r\n"))
+	}
+
+	f, ok := g.match(lines)
+	if !ok {
+		return Failure{}, false
+	}
+	f.Title = "Golden file mismatch"
+
+	for i, line := range lines {
+		if f.File == "" {
+			if file, n, _, ok := splitLocation(line); ok {
+				f.File, f.Line = file, n
+			}
+		}
+		trimmed := strings.TrimLeft(line, " \t")
+		if f.Diff != "" || !strings.HasPrefix(trimmed, "--- ") {
+			continue
+		}
+		indent := line[:len(line)-len(trimmed)]
+		rest := make([]string, 0, len(lines)-i)
+		for _, l := range lines[i:] {
+			rest = append(rest, strings.TrimPrefix(l, indent))
+		}
+		if _, n := diff.ReadHunks(rest); n > 0 {
+			f.Diff = strings.Join(rest[:n], "\n")
+		}
+	}
+	return f, true
+}
+
+// match returns a failure naming the golden file of the first pattern
+// that matches a line.
+func (g Golden) match(lines []string) (Failure, bool) {
+	for _, p := range g.Patterns {
+		for _, line := range lines {
+			m := p.Mismatch.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			file := p.File
+			if i := p.Mismatch.SubexpIndex("file"); i >= 0 && m[i] != "" {
+				file = m[i]
+			}
+			return Failure{Golden: file}, true
+		}
+	}
+	return Failure{}, false
+}
+
+// WithGoldenPatterns returns rs with patterns added to its Golden
+// recognizers.
+func WithGoldenPatterns(rs []Recognizer, patterns ...GoldenPattern) []Recognizer {
+	out := make([]Recognizer, len(rs))
+	for i, r := range rs {
+		if g, ok := r.(Golden); ok {
+			g.Patterns = append(append([]GoldenPattern(nil), g.Patterns...), patterns...)
+			r = g
+		}
+		out[i] = r
+	}
+	return out
+}
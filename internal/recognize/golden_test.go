@@ -0,0 +1,105 @@
+package recognize
+
+import (
+	"regexp"
+	"testing"
+)
+
+// gotestToolsGolden is the output of a failed gotest.tools golden.Assert.
+const gotestToolsGolden = "    render_test.go:21: assertion failed: \n" +
+	"        --- expected\n" +
+	"        +++ actual\n" +
+	"        @@ -1,3 +1,3 @@\n" +
+	"         <ul>\n" +
+	"        -<li>a</li>\n" +
+	"        +<li>b</li>\n" +
+	"         </ul>\n" +
+	"        \n" +
+	"        \n" +
+	"        You can run 'go test . -update' to automatically update testdata/list.golden to the new expected value.'\n"
+
+func TestGolden(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []GoldenPattern
+		output   string
+		want     Failure
+		ok       bool
+	}{
+		{
+			name:   "gotest.tools",
+			output: gotestToolsGolden,
+			want: Failure{
+				Title:  "Golden file mismatch",
+				File:   "render_test.go",
+				Line:   21,
+				Diff:   "--- expected\n+++ actual\n@@ -1,3 +1,3 @@\n <ul>\n-<li>a</li>\n+<li>b</li>\n </ul>",
+				Golden: "testdata/list.golden",
+			},
+			ok: true,
+		},
+		{
+			name: "goldie",
+			output: "    render_test.go:30: Result did not match the golden fixture. Diff is below:\n" +
+				"        \n" +
+				"        --- Expected\n" +
+				"        +++ Actual\n" +
+				"        @@ -1 +1 @@\n" +
+				"        -a\n" +
+				"        +b\n",
+			want: Failure{
+				Title:  "Golden file mismatch",
+				File:   "render_test.go",
+				Line:   30,
+				Diff:   "--- Expected\n+++ Actual\n@@ -1 +1 @@\n-a\n+b",
+				Golden: "testdata/{test}.golden",
+			},
+			ok: true,
+		},
+		{
+			name: "custom pattern",
+			patterns: []GoldenPattern{
+				{Mismatch: regexp.MustCompile(`golden (?P<file>\S+) is stale`)},
+			},
+			output: "    foo_test.go:8: golden testdata/out.txt is stale\n",
+			want: Failure{
+				Title:  "Golden file mismatch",
+				File:   "foo_test.go",
+				Line:   8,
+				Golden: "testdata/out.txt",
+			},
+			ok: true,
+		},
+		{
+			name:   "plain diff",
+			output: "    foo_test.go:8: mismatch\n--- a\n+++ b\n@@ -1 +1 @@\n-a\n+b\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := Golden{Patterns: GoldenPatterns()}
+			if tt.patterns != nil {
+				g.Patterns = tt.patterns
+			}
+			got, ok := g.Recognize(tt.output)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("Recognize() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestWithGoldenPatterns(t *testing.T) {
+	extra := GoldenPattern{Mismatch: regexp.MustCompile(`stale (?P<file>\S+)`)}
+	defaults := Default()
+
+	rs := WithGoldenPatterns(defaults, extra)
+	f, ok := Recognize(rs, "stale testdata/x.golden\n")
+	if !ok || f.Recognizer != "golden" || f.Golden != "testdata/x.golden" {
+		t.Errorf("Recognize() = %+v, %v, want the extra pattern to match", f, ok)
+	}
+	if _, ok := Recognize(defaults, "stale testdata/x.golden\n"); ok {
+		t.Error("WithGoldenPatterns() modified its argument")
+	}
+}
@@ -0,0 +1,62 @@
+package recognize
+
+import "strings"
+
+// GotestTools recognizes gotest.tools/v3/assert failures, which log
+// "file.go:12: assertion failed: ..." optionally followed by a diff.
+type GotestTools struct{}
+
+// Name implements Recognizer.
+func (GotestTools) Name() string { return "gotest.tools" }
+
+// Recognize implements Recognizer.
+func (GotestTools) Recognize(output string) (Failure, bool) {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	for i, line := range lines {
+		file, n, msg, ok := splitLocation(line)
+		if !ok {
+			continue
+		}
+		rest, ok := strings.CutPrefix(msg, "assertion failed:")
+		if !ok {
+			continue
+		}
+
+		f := Failure{File: file, Line: n, Title: "assertion failed"}
+		if rest = strings.TrimSpace(rest); rest != "" {
+			f.Title += ": " + rest
+			if got, want, ok := splitGotWant(rest); ok {
+				f.Actual, f.Expected = got, want
+			}
+		}
+
+		var diff []string
+		for _, l := range lines[i+1:] {
+			if _, _, _, ok := splitLocation(l); ok {
+				break
+			}
+			diff = append(diff, l)
+		}
+		f.Diff = dedent(diff)
+		return f, true
+	}
+	return Failure{}, false
+}
+
+// splitGotWant splits an assert.Equal message, "1 (got int) != 2 (want int)",
+// into its values.
+func splitGotWant(msg string) (got, want string, ok bool) {
+	left, right, ok := strings.Cut(msg, " != ")
+	if !ok {
+		return "", "", false
+	}
+	return trimAnnotation(left), trimAnnotation(right), true
+}
+
+// trimAnnotation removes a trailing "(name type)" annotation from a value.
+func trimAnnotation(v string) string {
+	if i := strings.LastIndex(v, " ("); i >= 0 && strings.HasSuffix(v, ")") {
+		return v[:i]
+	}
+	return v
+}
@@ -0,0 +1,55 @@
+package recognize
+
+import "testing"
+
+func TestGotestTools(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   Failure
+		ok     bool
+	}{
+		{
+			name:   "equal",
+			output: "foo_test.go:9: assertion failed: 1 (got int) != 2 (want int)\n",
+			want: Failure{
+				Title:    "assertion failed: 1 (got int) != 2 (want int)",
+				File:     "foo_test.go",
+				Line:     9,
+				Expected: "2",
+				Actual:   "1",
+			},
+			ok: true,
+		},
+		{
+			name: "deep equal",
+			output: "foo_test.go:11: assertion failed: \n" +
+				"    --- got\n" +
+				"    +++ want\n" +
+				"      []int{\n" +
+				"    - \t1,\n" +
+				"    + \t2,\n" +
+				"      }\n",
+			want: Failure{
+				Title: "assertion failed",
+				File:  "foo_test.go",
+				Line:  11,
+				Diff:  "--- got\n+++ want\n  []int{\n- \t1,\n+ \t2,\n  }",
+			},
+			ok: true,
+		},
+		{
+			name:   "plain log",
+			output: "foo_test.go:12: boom\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := GotestTools{}.Recognize(tt.output)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("Recognize() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
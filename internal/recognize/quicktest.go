@@ -0,0 +1,56 @@
+package recognize
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Quicktest recognizes github.com/frankban/quicktest failures, which print
+// indented sections such as "error:", "got:", "want:" and "stack:".
+type Quicktest struct{}
+
+var quicktestSectionRe = regexp.MustCompile(`^(\s*)(error|comment|got|want|diff \(-got \+want\)|stack):$`)
+
+// Name implements Recognizer.
+func (Quicktest) Name() string { return "quicktest" }
+
+// Recognize implements Recognizer.
+func (Quicktest) Recognize(output string) (Failure, bool) {
+	sections := make(map[string][]string)
+	var name, indent string
+	for line := range strings.Lines(output) {
+		line = strings.TrimRight(line, "\r\n")
+		if m := quicktestSectionRe.FindStringSubmatch(line); m != nil && (name == "" || m[1] == indent) {
+			name, indent = m[2], m[1]
+			sections[name] = []string{}
+			continue
+		}
+		if name != "" && (strings.HasPrefix(line, indent+" ") || strings.TrimSpace(line) == "") {
+			sections[name] = append(sections[name], line)
+			continue
+		}
+		name = ""
+	}
+
+	errLines, ok := sections["error"]
+	if !ok {
+		return Failure{}, false
+	}
+
+	f := Failure{
+		Title:    dedent(errLines),
+		Actual:   dedent(sections["got"]),
+		Expected: dedent(sections["want"]),
+		Diff:     dedent(sections["diff (-got +want)"]),
+	}
+	if comment := dedent(sections["comment"]); comment != "" {
+		f.Title += ": " + comment
+	}
+	for _, l := range sections["stack"] {
+		if file, line, ok := parseFileLine(l); ok {
+			f.File, f.Line = file, line
+			break
+		}
+	}
+	return f, true
+}
@@ -0,0 +1,68 @@
+package recognize
+
+import "testing"
+
+func TestQuicktest(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   Failure
+		ok     bool
+	}{
+		{
+			name: "equals",
+			output: "\n" +
+				"error:\n" +
+				"  values are not equal\n" +
+				"comment:\n" +
+				"  checking sum\n" +
+				"got:\n" +
+				"  int(3)\n" +
+				"want:\n" +
+				"  int(4)\n" +
+				"stack:\n" +
+				"  /src/app/foo/foo_test.go:14\n" +
+				"    c.Assert(sum, qt.Equals, 4)\n",
+			want: Failure{
+				Title:    "values are not equal: checking sum",
+				File:     "/src/app/foo/foo_test.go",
+				Line:     14,
+				Expected: "int(4)",
+				Actual:   "int(3)",
+			},
+			ok: true,
+		},
+		{
+			name: "deep equals",
+			output: "error:\n" +
+				"  values are not deep equal\n" +
+				"diff (-got +want):\n" +
+				"    []int{\n" +
+				"  - \t1,\n" +
+				"  + \t2,\n" +
+				"    }\n" +
+				"stack:\n" +
+				"  /src/app/foo/foo_test.go:20\n",
+			want: Failure{
+				Title: "values are not deep equal",
+				File:  "/src/app/foo/foo_test.go",
+				Line:  20,
+				Diff:  "  []int{\n- \t1,\n+ \t2,\n  }",
+			},
+			ok: true,
+		},
+		{
+			name:   "plain log",
+			output: "foo_test.go:12: boom\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Quicktest{}.Recognize(tt.output)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("Recognize() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Package recognize extracts structured failure details from test output.
+package recognize
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Failure is the structured description of a failed assertion.
+type Failure struct {
+	// Recognizer names the recognizer that produced the failure.
+	Recognizer string
+	// Title is a concise one-line description of the failure.
+	Title string
+	// File is the source file of the failing assertion as printed, either
+	// a bare file name or a path.
+	File string
+	// Line is the 1-based line of the failing assertion, or 0 if unknown.
+	Line int
+	// Expected is the expected value, if printed.
+	Expected string
+	// Actual is the actual value, if printed.
+	Actual string
+	// Diff is a diff between the expected and actual values, if printed.
+	Diff string
+	// Command is the failing command of a script test.
+	Command string
+	// Stdout is the standard output captured by a script test.
+	Stdout string
+	// Stderr is the standard error captured by a script test.
+	Stderr string
+	// Golden is the golden file that did not match the output, relative
+	// to the package directory, with {test} standing for the test name.
+	Golden string
+}
+
+// Structured reports whether the failure carries more than a location
+// and title.
+func (f Failure) Structured() bool {
+	return f.Expected != "" || f.Actual != "" || f.Diff != "" ||
+		f.Command != "" || f.Stdout != "" || f.Stderr != "" || f.Golden != ""
+}
+
+// Recognizer extracts failure details from the output of a failed test.
+type Recognizer interface {
+	// Name identifies the recognizer, usually after the library it handles.
+	Name() string
+	// Recognize returns the failure described by output, if recognized.
+	Recognize(output string) (Failure, bool)
+}
+
+// Default returns the built-in recognizers, most specific first.
+func Default() []Recognizer {
+	return []Recognizer{
+		Testscript{},
+		Golden{Patterns: GoldenPatterns()},
+		Testify{},
+		Quicktest{},
+		GoCmp{},
+		GotestTools{},
+		Testing{},
+	}
+}
+
+// Recognize returns the failure found by the first recognizer in rs that
+// recognizes output.
+func Recognize(rs []Recognizer, output string) (Failure, bool) {
+	for _, r := range rs {
+		if f, ok := r.Recognize(output); ok {
+			f.Recognizer = r.Name()
+			return f, true
+		}
+	}
+	return Failure{}, false
+}
+
+// locationRe matches the "file.go:12: message" prefix the testing package
+// adds to log lines.
+var locationRe = regexp.MustCompile(`^\s*([^\s:]+\.go):(\d+): ?(.*)$`)
+
+// splitLocation splits a testing log line into its location and message.
+func splitLocation(line string) (file string, lineNum int, msg string, ok bool) {
+	m := locationRe.FindStringSubmatch(line)
+	if m == nil {
+		return "", 0, "", false
+	}
+	lineNum, _ = strconv.Atoi(m[2])
+	return m[1], lineNum, m[3], true
+}
+
+// fileLineRe matches a "path/file.go:12" reference.
+var fileLineRe = regexp.MustCompile(`^(\S+\.go):(\d+)`)
+
+// parseFileLine parses a "path/file.go:12" reference at the start of s.
+func parseFileLine(s string) (file string, line int, ok bool) {
+	m := fileLineRe.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return "", 0, false
+	}
+	line, _ = strconv.Atoi(m[2])
+	return m[1], line, true
+}
+
+// dedent removes the indentation common to all non-blank lines.
+func dedent(lines []string) string {
+	indent := -1
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		n := len(l) - len(strings.TrimLeft(l, " \t"))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		if len(l) >= indent && indent > 0 {
+			l = l[indent:]
+		}
+		out[i] = strings.TrimRight(l, " \t")
+	}
+	return strings.Trim(strings.Join(out, "\n"), "\n")
+}
@@ -0,0 +1,39 @@
+package recognize
+
+import "testing"
+
+func TestRecognize(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{"testscript", testscriptExec, "testscript"},
+		{"golden", gotestToolsGolden, "golden"},
+		{"testify", testifyEqual, "testify"},
+		{"go-cmp", "foo_test.go:15: Parse() mismatch (-want +got):\n  -a\n  +b\n", "go-cmp"},
+		{"gotest.tools", "foo_test.go:9: assertion failed: x is false\n", "gotest.tools"},
+		{"quicktest", "error:\n  got non-nil error\nstack:\n  foo_test.go:3\n", "quicktest"},
+		{"testing", "foo_test.go:12: boom\n", "testing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, ok := Recognize(Default(), tt.output)
+			if !ok || f.Recognizer != tt.want {
+				t.Errorf("Recognize() recognizer = %q, %v, want %q", f.Recognizer, ok, tt.want)
+			}
+		})
+	}
+
+	if _, ok := Recognize(Default(), "panic: boom\n"); ok {
+		t.Error("Recognize() recognized unstructured output")
+	}
+}
+
+func TestDedent(t *testing.T) {
+	got := dedent([]string{"", "    a", "      b ", "", "    c", ""})
+	if want := "a\n  b\n\nc"; got != want {
+		t.Errorf("dedent() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,106 @@
+package recognize
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Testify recognizes failures reported by github.com/stretchr/testify,
+// which print a block of labeled fields:
+//
+//	Error Trace:	/src/foo/foo_test.go:12
+//	Error:      	Not equal:
+//	            	expected: 1
+//	            	actual  : 2
+//	Test:       	TestFoo
+type Testify struct{}
+
+var (
+	testifyLabelRe = regexp.MustCompile(`^\s*\t([A-Za-z][A-Za-z ]*):\s*\t(.*)$`)
+	testifyContRe  = regexp.MustCompile(`^\s*\t\s*\t(.*)$`)
+)
+
+// Name implements Recognizer.
+func (Testify) Name() string { return "testify" }
+
+// Recognize implements Recognizer.
+func (Testify) Recognize(output string) (Failure, bool) {
+	fields := make(map[string][]string)
+	var label string
+	for line := range strings.Lines(output) {
+		line = strings.TrimRight(line, "\r\n")
+		if m := testifyLabelRe.FindStringSubmatch(line); m != nil {
+			label = m[1]
+			fields[label] = append(fields[label], m[2])
+			continue
+		}
+		if m := testifyContRe.FindStringSubmatch(line); m != nil && label != "" {
+			fields[label] = append(fields[label], m[1])
+			continue
+		}
+		label = ""
+	}
+
+	trace, ok := fields["Error Trace"]
+	if !ok {
+		return Failure{}, false
+	}
+
+	var f Failure
+	for _, entry := range trace {
+		if file, line, ok := parseFileLine(entry); ok {
+			f.File, f.Line = file, line
+			break
+		}
+	}
+	parseTestifyError(&f, fields["Error"])
+	if msgs := strings.TrimSpace(strings.Join(fields["Messages"], " ")); msgs != "" {
+		f.Title += ": " + msgs
+	}
+	return f, true
+}
+
+// parseTestifyError fills in the title and values from the lines of the
+// "Error:" field.
+func parseTestifyError(f *Failure, lines []string) {
+	var section string
+	var title, expected, actual, diff []string
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case i == 0:
+			title = append(title, strings.TrimSuffix(trimmed, ":"))
+			continue
+		case strings.HasPrefix(trimmed, "expected:"):
+			section = "expected"
+			line = strings.TrimPrefix(trimmed, "expected:")
+		case strings.HasPrefix(trimmed, "actual"):
+			if rest, ok := strings.CutPrefix(strings.TrimLeft(trimmed[len("actual"):], " "), ":"); ok {
+				section = "actual"
+				line = rest
+			}
+		case trimmed == "Diff:":
+			section = "diff"
+			continue
+		case section == "" && i == 1 && trimmed != "":
+			// "Received unexpected error:" and similar titles continue
+			// on the next line.
+			title = append(title, trimmed)
+			continue
+		}
+
+		switch section {
+		case "expected":
+			expected = append(expected, line)
+		case "actual":
+			actual = append(actual, line)
+		case "diff":
+			diff = append(diff, line)
+		}
+	}
+
+	f.Title = strings.Join(title, ": ")
+	f.Expected = dedent(expected)
+	f.Actual = dedent(actual)
+	f.Diff = dedent(diff)
+}
@@ -0,0 +1,91 @@
+package recognize
+
+import "testing"
+
+const testifyEqual = "foo_test.go:12: \n" +
+	"    \tError Trace:\t/src/app/foo/foo_test.go:12\n" +
+	"    \t            \t/src/app/foo/helper_test.go:30\n" +
+	"    \tError:      \tNot equal: \n" +
+	"    \t            \texpected: 1\n" +
+	"    \t            \tactual  : 2\n" +
+	"    \tTest:       \tTestFoo\n" +
+	"    \tMessages:   \tvalues differ\n"
+
+const testifyDiff = "foo_test.go:20: \n" +
+	"    \tError Trace:\tfoo_test.go:20\n" +
+	"    \tError:      \tNot equal: \n" +
+	"    \t            \texpected: []int{1, 2}\n" +
+	"    \t            \tactual  : []int{1, 3}\n" +
+	"    \t            \t\n" +
+	"    \t            \tDiff:\n" +
+	"    \t            \t--- Expected\n" +
+	"    \t            \t+++ Actual\n" +
+	"    \t            \t@@ -2,3 +2,3 @@\n" +
+	"    \t            \t  (int) 1,\n" +
+	"    \t            \t- (int) 2\n" +
+	"    \t            \t+ (int) 3\n" +
+	"    \tTest:       \tTestFoo\n"
+
+const testifyError = "foo_test.go:8: \n" +
+	"    \tError Trace:\t/src/app/foo/foo_test.go:8\n" +
+	"    \tError:      \tReceived unexpected error:\n" +
+	"    \t            \topen x: no such file or directory\n" +
+	"    \tTest:       \tTestOpen\n"
+
+func TestTestify(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   Failure
+		ok     bool
+	}{
+		{
+			name:   "equal",
+			output: testifyEqual,
+			want: Failure{
+				Title:    "Not equal: values differ",
+				File:     "/src/app/foo/foo_test.go",
+				Line:     12,
+				Expected: "1",
+				Actual:   "2",
+			},
+			ok: true,
+		},
+		{
+			name:   "diff",
+			output: testifyDiff,
+			want: Failure{
+				Title:    "Not equal",
+				File:     "foo_test.go",
+				Line:     20,
+				Expected: "[]int{1, 2}",
+				Actual:   "[]int{1, 3}",
+				Diff:     "--- Expected\n+++ Actual\n@@ -2,3 +2,3 @@\n  (int) 1,\n- (int) 2\n+ (int) 3",
+			},
+			ok: true,
+		},
+		{
+			name:   "unexpected error",
+			output: testifyError,
+			want: Failure{
+				Title: "Received unexpected error: open x: no such file or directory",
+				File:  "/src/app/foo/foo_test.go",
+				Line:  8,
+			},
+			ok: true,
+		},
+		{
+			name:   "plain log",
+			output: "foo_test.go:12: boom\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Testify{}.Recognize(tt.output)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("Recognize() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package recognize
+
+import "strings"
+
+// Testing recognizes plain testing.T log output, "file.go:12: message",
+// and reports the first logged line as the failure.
+type Testing struct{}
+
+// Name implements Recognizer.
+func (Testing) Name() string { return "testing" }
+
+// Recognize implements Recognizer.
+func (Testing) Recognize(output string) (Failure, bool) {
+	for line := range strings.Lines(output) {
+		if file, n, msg, ok := splitLocation(strings.TrimRight(line, "\n")); ok {
+			return Failure{Title: strings.TrimSpace(msg), File: file, Line: n}, true
+		}
+	}
+	return Failure{}, false
+}
@@ -0,0 +1,15 @@
+package recognize
+
+import "testing"
+
+func TestTesting(t *testing.T) {
+	got, ok := Testing{}.Recognize("=== setup\nfoo_test.go:12: boom \nfoo_test.go:13: again\n")
+	want := Failure{Title: "boom", File: "foo_test.go", Line: 12}
+	if !ok || got != want {
+		t.Errorf("Recognize() = %+v, %v, want %+v, true", got, ok, want)
+	}
+
+	if _, ok := (Testing{}).Recognize("panic: boom\n"); ok {
+		t.Error("Recognize() recognized output without a location")
+	}
+}
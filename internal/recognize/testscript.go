@@ -0,0 +1,100 @@
+package recognize
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Testscript recognizes github.com/rogpeppe/go-internal/testscript
+// failures. The script log echoes each command as "> cmd", followed by
+// its "[stdout]" and "[stderr]" sections, and ends with
+// "FAIL: testdata/script/foo.txtar:12: message".
+type Testscript struct{}
+
+var (
+	// testscriptFailRe matches the line naming the failing script line.
+	testscriptFailRe = regexp.MustCompile(`^FAIL: (\S+):(\d+): (.*)$`)
+	// testscriptSectionRe matches the headers of captured output and of
+	// the exit status.
+	testscriptSectionRe = regexp.MustCompile(`^\[(stdout|stderr|exit status \d+|signal: .+)\]$`)
+	// testscriptCommentRe matches the timed comments separating script
+	// sections.
+	testscriptCommentRe = regexp.MustCompile(`^# .* \(\d+\.\d+s\)$`)
+)
+
+// Name implements Recognizer.
+func (Testscript) Name() string { return "testscript" }
+
+// Recognize implements Recognizer.
+func (Testscript) Recognize(output string) (Failure, bool) {
+	var log []string
+	for line := range strings.Lines(output) {
+		line = strings.TrimRight(line, "\r\n")
+		if _, _, msg, ok := splitLocation(line); ok {
+			// The log starts on the line of the t.Log call.
+			line = msg
+		}
+		log = append(log, line)
+	}
+
+	// Script lines are indented alike, so the failure line gives the
+	// indentation to strip.
+	end, indent := -1, ""
+	var m []string
+	for i, line := range log {
+		trimmed := strings.TrimLeft(line, " \t")
+		if m = testscriptFailRe.FindStringSubmatch(trimmed); m != nil {
+			end, indent = i, line[:len(line)-len(trimmed)]
+			break
+		}
+	}
+	if end < 0 {
+		return Failure{}, false
+	}
+
+	f := Failure{Title: m[3], File: m[1]}
+	f.Line, _ = strconv.Atoi(m[2])
+
+	var section string
+	var stdout, stderr, after []string
+	for _, line := range log[:end] {
+		line = strings.TrimPrefix(line, indent)
+		switch {
+		case strings.HasPrefix(line, "> "):
+			f.Command, section, after = strings.TrimPrefix(line, "> "), "", nil
+			continue
+		case testscriptSectionRe.MatchString(line):
+			section = strings.Trim(line, "[]")
+			switch section {
+			case "stdout":
+				stdout = []string{}
+			case "stderr":
+				stderr = []string{}
+			}
+			continue
+		case testscriptCommentRe.MatchString(line):
+			section = ""
+			continue
+		}
+
+		switch section {
+		case "stdout":
+			stdout = append(stdout, line)
+		case "stderr":
+			stderr = append(stderr, line)
+		case "":
+			if f.Command != "" {
+				after = append(after, line)
+			}
+		}
+	}
+	f.Stdout = strings.Trim(strings.Join(stdout, "\n"), "\n")
+	f.Stderr = strings.Trim(strings.Join(stderr, "\n"), "\n")
+
+	// cmp prints a unified diff of the compared files before failing.
+	if len(after) > 0 && strings.HasPrefix(after[0], "diff ") {
+		f.Diff = strings.Trim(strings.Join(after[1:], "\n"), "\n")
+	}
+	return f, true
+}
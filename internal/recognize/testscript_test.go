@@ -0,0 +1,95 @@
+package recognize
+
+import "testing"
+
+// testscriptExec is the log of a script whose exec failed.
+const testscriptExec = "    testscript.go:584: WORK=$WORK\n" +
+	"        PATH=/usr/bin\n" +
+	"        \n" +
+	"        # greet the user (0.003s)\n" +
+	"        > exec hello --name gopher\n" +
+	"        [stdout]\n" +
+	"        hello,\n" +
+	"          gopher\n" +
+	"        [stderr]\n" +
+	"        warning: no config\n" +
+	"        [exit status 1]\n" +
+	"        FAIL: testdata/script/hello.txtar:2: unexpected command failure\n" +
+	"        \n"
+
+func TestTestscript(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   Failure
+		ok     bool
+	}{
+		{
+			name:   "exec",
+			output: testscriptExec,
+			want: Failure{
+				Title:   "unexpected command failure",
+				File:    "testdata/script/hello.txtar",
+				Line:    2,
+				Command: "exec hello --name gopher",
+				Stdout:  "hello,\n  gopher",
+				Stderr:  "warning: no config",
+			},
+			ok: true,
+		},
+		{
+			name: "stdout match after exec",
+			output: "    testscript.go:584: # default (0.000s)\n" +
+				"        > exec hello\n" +
+				"        [stdout]\n" +
+				"        hi\n" +
+				"        > stdout 'bye'\n" +
+				"        FAIL: hello.txt:3: no match for `bye` found in stdout\n",
+			want: Failure{
+				Title:   "no match for `bye` found in stdout",
+				File:    "hello.txt",
+				Line:    3,
+				Command: "stdout 'bye'",
+				Stdout:  "hi",
+			},
+			ok: true,
+		},
+		{
+			name: "cmp",
+			output: "    testscript.go:584: > exec hello\n" +
+				"        [stdout]\n" +
+				"        hi\n" +
+				"        > cmp stdout want\n" +
+				"        diff stdout want\n" +
+				"        --- stdout\n" +
+				"        +++ want\n" +
+				"        @@ -1 +1 @@\n" +
+				"        -hi\n" +
+				"        +bye\n" +
+				"        \n" +
+				"        FAIL: testdata/script/cmp.txtar:4: stdout and want differ\n",
+			want: Failure{
+				Title:   "stdout and want differ",
+				File:    "testdata/script/cmp.txtar",
+				Line:    4,
+				Command: "cmp stdout want",
+				Stdout:  "hi",
+				Diff:    "--- stdout\n+++ want\n@@ -1 +1 @@\n-hi\n+bye",
+			},
+			ok: true,
+		},
+		{
+			name:   "plain log",
+			output: "foo_test.go:12: FAIL: boom\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Testscript{}.Recognize(tt.output)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("Recognize() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
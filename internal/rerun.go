@@ -0,0 +1,243 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// rerunCommand is a go test command rerunning the failed tests of a
+// package. Tests whose subtests all passed are rerun with those subtests
+// skipped.
+type rerunCommand struct {
+	// Package is the import path of the package.
+	Package string `json:"package"`
+	// Run is the -run pattern, or empty to run the whole package.
+	Run string `json:"run,omitempty"`
+	// Skip is the -skip pattern, if any.
+	Skip string `json:"skip,omitempty"`
+	// Flags are the other flags of the failed run.
+	Flags []string `json:"flags,omitempty"`
+	// Tests are the failed tests the command reruns.
+	Tests []string `json:"tests,omitempty"`
+	// Command is the command line.
+	Command string `json:"command"`
+}
+
+// packageOutcome collects the outcome of the tests of a package.
+type packageOutcome struct {
+	pkg    string
+	failed bool
+	fails  map[string]bool
+	passes map[string]bool
+	flags  runFlags
+}
+
+// rerunPlan returns the commands rerunning the failed tests of the go
+// test inputs, in order of first appearance of their packages. A test
+// that failed in any run is rerun. Failed tests under the same parent
+// share a command, and packages that failed without a failed test are
+// rerun whole.
+func rerunPlan(inputs []input) []rerunCommand {
+	index := make(map[string]*packageOutcome)
+	var order []*packageOutcome
+	for _, in := range inputs {
+		if in.Format != formatTestJSON && in.Format != formatBazelTestlogs {
+			continue
+		}
+		flags := collectRunFlags(in.Events)
+		for _, c := range collectTests(in.Events) {
+			if c.pkg == "" {
+				continue
+			}
+			o := index[c.pkg]
+			if o == nil {
+				o = &packageOutcome{pkg: c.pkg, fails: make(map[string]bool), passes: make(map[string]bool)}
+				index[c.pkg] = o
+				order = append(order, o)
+			}
+			if f, ok := flags[c.pkg]; ok {
+				o.flags = f
+			}
+			switch {
+			case c.Action != "fail" && c.Action != "pass":
+			case c.test == "":
+				o.failed = o.failed || c.Action == "fail"
+			case c.Action == "fail":
+				o.fails[c.test] = true
+			default:
+				o.passes[c.test] = true
+			}
+		}
+	}
+
+	var plan []rerunCommand
+	for _, o := range order {
+		plan = append(plan, o.commands()...)
+	}
+	return plan
+}
+
+// commands returns the commands rerunning the failures of o.
+func (o *packageOutcome) commands() []rerunCommand {
+	// Rerun the innermost failed tests; their parents failed because of
+	// them.
+	var leaves []string
+	for test := range o.fails {
+		inner := false
+		for other := range o.fails {
+			if strings.HasPrefix(other, test+"/") {
+				inner = true
+				break
+			}
+		}
+		if !inner {
+			leaves = append(leaves, test)
+		}
+	}
+	slices.Sort(leaves)
+
+	if len(leaves) == 0 {
+		if !o.failed {
+			return nil
+		}
+		return []rerunCommand{o.command("", "", nil)}
+	}
+
+	groups := make(map[string][]string)
+	var parents []string
+	for _, test := range leaves {
+		parent, _ := splitTestName(test)
+		if groups[parent] == nil {
+			parents = append(parents, parent)
+		}
+		groups[parent] = append(groups[parent], test)
+	}
+
+	var out []rerunCommand
+	for _, parent := range parents {
+		tests := groups[parent]
+		var names, withPasses, passed []string
+		for _, test := range tests {
+			_, name := splitTestName(test)
+			names = append(names, name)
+			children := o.passedChildren(test)
+			if len(children) > 0 {
+				withPasses = append(withPasses, name)
+			}
+			passed = append(passed, children...)
+		}
+		prefix := ""
+		if parent != "" {
+			prefix = runPattern(parent) + "/"
+		}
+		run := prefix + anchoredAlternation(names)
+		var skip string
+		if len(passed) > 0 {
+			slices.Sort(passed)
+			skip = prefix + anchoredAlternation(withPasses) + "/" + anchoredAlternation(slices.Compact(passed))
+		}
+		out = append(out, o.command(run, skip, tests))
+	}
+	return out
+}
+
+// passedChildren returns the names of the direct subtests of test that
+// passed and never failed.
+func (o *packageOutcome) passedChildren(test string) []string {
+	var out []string
+	for passed := range o.passes {
+		parent, name := splitTestName(passed)
+		if parent == test && !o.fails[passed] {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// command returns the command rerunning tests with the given patterns.
+func (o *packageOutcome) command(run, skip string, tests []string) rerunCommand {
+	args := []string{"go", "test"}
+	if run != "" {
+		args = append(args, "-run", shellQuote(run))
+	}
+	if skip != "" {
+		args = append(args, "-skip", shellQuote(skip))
+	}
+	flags := o.flags.args()
+	for _, a := range flags {
+		args = append(args, shellQuote(a))
+	}
+	args = append(args, shellQuote(o.pkg))
+	return rerunCommand{
+		Package: o.pkg,
+		Run:     run,
+		Skip:    skip,
+		Flags:   flags,
+		Tests:   tests,
+		Command: strings.Join(args, " "),
+	}
+}
+
+// splitTestName splits a test name into the name of its parent, empty for
+// a top-level test, and its own name.
+func splitTestName(test string) (parent, name string) {
+	i := strings.LastIndexByte(test, '/')
+	if i < 0 {
+		return "", test
+	}
+	return test[:i], test[i+1:]
+}
+
+// anchoredAlternation returns a pattern matching exactly one of names at
+// one level of a -run pattern.
+func anchoredAlternation(names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = regexp.QuoteMeta(n)
+	}
+	if len(quoted) == 1 {
+		return "^" + quoted[0] + "$"
+	}
+	return "^(" + strings.Join(quoted, "|") + ")$"
+}
+
+// writeRerun writes the rerun plan of the inputs to the file selected by
+// opts, if any: as JSON when its name ends in ".json", and as a shell
+// script otherwise.
+func writeRerun(inputs []input, opts ConvertOptions) error {
+	if opts.Rerun == "" {
+		return nil
+	}
+	plan := rerunPlan(inputs)
+
+	var buf bytes.Buffer
+	mode := os.FileMode(0o755)
+	if strings.HasSuffix(opts.Rerun, ".json") {
+		if plan == nil {
+			plan = []rerunCommand{}
+		}
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(struct {
+			Commands []rerunCommand `json:"commands"`
+		}{plan}); err != nil {
+			return err
+		}
+		mode = 0o644
+	} else {
+		buf.WriteString("#!/bin/sh\n# Reruns the tests that failed.\nstatus=0\n")
+		for _, c := range plan {
+			fmt.Fprintf(&buf, "%s || status=1\n", c.Command)
+		}
+		buf.WriteString("exit $status\n")
+	}
+	if err := os.WriteFile(opts.Rerun, buf.Bytes(), mode); err != nil {
+		return fmt.Errorf("writing rerun plan: %w", err)
+	}
+	return nil
+}
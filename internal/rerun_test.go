@@ -0,0 +1,111 @@
+package internal
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/testjson"
+)
+
+func TestRerunPlan(t *testing.T) {
+	events := []testjson.TestEvent{
+		{Action: "start", Package: testPkg},
+		{Action: "output", Package: testPkg, Output: "-test.shuffle 42\n"},
+		{Action: "run", Package: testPkg, Test: "TestTable"},
+	}
+	events = append(events, runEvents("TestTable/case_1", "fail")...)
+	events = append(events, runEvents("TestTable/case.2", "fail")...)
+	events = append(events, runEvents("TestTable/ok", "pass")...)
+	events = append(events, testjson.TestEvent{Action: "fail", Package: testPkg, Test: "TestTable"})
+	events = append(events, testjson.TestEvent{Action: "run", Package: testPkg, Test: "TestSetup"})
+	events = append(events, runEvents("TestSetup/fast", "pass")...)
+	events = append(events, runEvents("TestSetup/slow", "pass")...)
+	events = append(events, testjson.TestEvent{Action: "fail", Package: testPkg, Test: "TestSetup"})
+	events = append(events, runEvents("TestPlain", "fail")...)
+	events = append(events, runEvents("TestPassed", "pass")...)
+	events = append(events, testjson.TestEvent{Action: "fail", Package: testPkg})
+	events = append(events,
+		testjson.TestEvent{Action: "output", Package: "example.com/app/db", Output: "panic: boom\n"},
+		testjson.TestEvent{Action: "fail", Package: "example.com/app/db"},
+		testjson.TestEvent{Action: "pass", Package: "example.com/app/ok"},
+	)
+
+	got := rerunPlan([]input{{Path: "test.json", Events: events}})
+	want := []rerunCommand{
+		{
+			Package: testPkg,
+			Run:     "^(TestPlain|TestSetup)$",
+			Skip:    "^TestSetup$/^(fast|slow)$",
+			Flags:   []string{"-shuffle=42"},
+			Tests:   []string{"TestPlain", "TestSetup"},
+			Command: "go test -run '^(TestPlain|TestSetup)$' -skip '^TestSetup$/^(fast|slow)$' -shuffle=42 example.com/app/foo",
+		},
+		{
+			Package: testPkg,
+			Run:     `^TestTable$/^(case\.2|case_1)$`,
+			Flags:   []string{"-shuffle=42"},
+			Tests:   []string{"TestTable/case.2", "TestTable/case_1"},
+			Command: `go test -run '^TestTable$/^(case\.2|case_1)$' -shuffle=42 example.com/app/foo`,
+		},
+		{
+			Package: "example.com/app/db",
+			Command: "go test example.com/app/db",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rerunPlan() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestWriteRerun(t *testing.T) {
+	dir := t.TempDir()
+	in := input{Path: "test.json", Events: runEvents("TestA", "fail")}
+
+	script := filepath.Join(dir, "rerun.sh")
+	if err := writeRerun([]input{in}, ConvertOptions{Rerun: script}); err != nil {
+		t.Fatalf("writeRerun returned error: %v", err)
+	}
+	data, err := os.ReadFile(script)
+	if err != nil {
+		t.Fatalf("failed to read script: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "#!/bin/sh\n") || !strings.Contains(string(data), "go test -run '^TestA$' example.com/app/foo || status=1\n") {
+		t.Errorf("script = %q", data)
+	}
+
+	plan := filepath.Join(dir, "rerun.json")
+	if err := writeRerun([]input{in}, ConvertOptions{Rerun: plan}); err != nil {
+		t.Fatalf("writeRerun returned error: %v", err)
+	}
+	if data, err = os.ReadFile(plan); err != nil {
+		t.Fatalf("failed to read plan: %v", err)
+	}
+	var doc struct {
+		Commands []rerunCommand `json:"commands"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid plan %s: %v", data, err)
+	}
+	if len(doc.Commands) != 1 || doc.Commands[0].Run != "^TestA$" {
+		t.Errorf("commands = %+v", doc.Commands)
+	}
+}
+
+func TestBuildReport_Reproduce(t *testing.T) {
+	in := input{Path: "test.json", Events: runEvents("TestA/it's", "fail", "    foo_test.go:3: boom\n")}
+	results := buildReport([]input{in}, ConvertOptions{}).Results
+	if len(results) != 1 {
+		t.Fatalf("results = %+v, want one", results)
+	}
+	want := `go test -run '^TestA$/^it'\''s$' example.com/app/foo`
+	if got := results[0].Properties["reproduce"]; got != want {
+		t.Errorf("reproduce = %v, want %s", got, want)
+	}
+	if strings.Contains(results[0].Message, "Reproduce with") {
+		t.Errorf("message = %q, want no command without flags", results[0].Message)
+	}
+}
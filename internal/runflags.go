@@ -0,0 +1,204 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/bench"
+	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
+	"github.com/ivuorinen/go-test-sarif-action/internal/testjson"
+)
+
+// runFlags are the go test flags of a package run that can be told from
+// its output.
+type runFlags struct {
+	// Shuffle is the seed printed by -shuffle.
+	Shuffle string
+	// Count is the -count each test ran with, if more than one. It is
+	// known only when CPU is: -cpu also runs each test once per value.
+	Count int
+	// Runs is the number of times each test ran, if more than once, when
+	// it cannot be told whether -count or -cpu repeated them.
+	Runs int
+	// Race is set when the race detector reported a race.
+	Race bool
+	// Timeout is the -timeout that expired.
+	Timeout string
+	// CPU are the GOMAXPROCS values benchmarks ran with, when -cpu selected
+	// more than one.
+	CPU []int
+}
+
+var (
+	// shuffleRe matches the seed line printed by -shuffle=on.
+	shuffleRe = regexp.MustCompile(`^-test\.shuffle (\d+)$`)
+	// timeoutRe matches the panic of a test binary that ran out of time.
+	timeoutRe = regexp.MustCompile(`^panic: test timed out after (\S+)$`)
+)
+
+// empty reports whether no flags were seen.
+func (f runFlags) empty() bool {
+	return f.Shuffle == "" && f.Count <= 1 && f.Runs <= 1 && !f.Race && f.Timeout == "" && len(f.CPU) == 0
+}
+
+// args returns the flags as go test arguments.
+func (f runFlags) args() []string {
+	var args []string
+	if f.Shuffle != "" {
+		args = append(args, "-shuffle="+f.Shuffle)
+	}
+	if f.Count > 1 {
+		args = append(args, "-count="+strconv.Itoa(f.Count))
+	}
+	if f.Race {
+		args = append(args, "-race")
+	}
+	if f.Timeout != "" {
+		args = append(args, "-timeout="+f.Timeout)
+	}
+	if len(f.CPU) > 0 {
+		cpus := make([]string, len(f.CPU))
+		for i, n := range f.CPU {
+			cpus[i] = strconv.Itoa(n)
+		}
+		args = append(args, "-cpu="+strings.Join(cpus, ","))
+	}
+	return args
+}
+
+// packageRun accumulates the flags of a package run from its events.
+type packageRun struct {
+	flags runFlags
+	runs  map[string]int
+	most  int
+	cpus  []int
+}
+
+// collectRunFlags returns the flags of the last run of each package in
+// events.
+func collectRunFlags(events []testjson.TestEvent) map[string]runFlags {
+	runs := make(map[string]*packageRun)
+	for _, e := range events {
+		if e.Package == "" {
+			continue
+		}
+		r := runs[e.Package]
+		if r == nil || (e.Action == "start" && e.Test == "") {
+			r = &packageRun{runs: make(map[string]int)}
+			runs[e.Package] = r
+		}
+		switch e.Action {
+		case "run":
+			r.runs[e.Test]++
+			r.most = max(r.most, r.runs[e.Test])
+		case "output":
+			line := strings.TrimSpace(e.Output)
+			if m := shuffleRe.FindStringSubmatch(line); m != nil && e.Test == "" {
+				r.flags.Shuffle = m[1]
+			}
+			if m := timeoutRe.FindStringSubmatch(line); m != nil {
+				r.flags.Timeout = m[1]
+			}
+			if line == "WARNING: DATA RACE" || strings.HasSuffix(line, "race detected during execution of test") {
+				r.flags.Race = true
+			}
+			if res, ok := bench.ParseLine(e.Output); ok && !slices.Contains(r.cpus, res.Procs) {
+				r.cpus = append(r.cpus, res.Procs)
+			}
+		}
+	}
+
+	out := make(map[string]runFlags, len(runs))
+	for pkg, r := range runs {
+		switch {
+		case len(r.cpus) > 1:
+			r.flags.CPU = slices.Sorted(slices.Values(r.cpus))
+			if count := r.most / len(r.cpus); count > 1 {
+				r.flags.Count = count
+			}
+		case r.most > 1:
+			r.flags.Runs = r.most
+		}
+		out[pkg] = r.flags
+	}
+	return out
+}
+
+// runPattern returns a -run pattern selecting exactly test, anchoring
+// each level of its name.
+func runPattern(test string) string {
+	parts := strings.Split(test, "/")
+	for i, p := range parts {
+		parts[i] = "^" + regexp.QuoteMeta(p) + "$"
+	}
+	return strings.Join(parts, "/")
+}
+
+// shellQuote quotes s for a POSIX shell if needed.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !strings.ContainsRune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_=./,:@+", r)
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// reproduceCommand returns a go test command running test, or the whole
+// package when test is empty, with flags.
+func reproduceCommand(pkg, test string, flags runFlags) string {
+	args := []string{"go", "test"}
+	if test != "" {
+		args = append(args, "-run", shellQuote(runPattern(test)))
+	}
+	for _, a := range flags.args() {
+		args = append(args, shellQuote(a))
+	}
+	return strings.Join(append(args, shellQuote(pkg)), " ")
+}
+
+// applyRunFlags records a command reproducing result in its properties.
+// When the run that produced it had flags of its own, they are recorded
+// too and the command is returned, to be given in the message with
+// appendReproduce.
+func applyRunFlags(result *sarif.Result, pkg, test string, flags runFlags) string {
+	if result.Properties == nil {
+		result.Properties = map[string]any{}
+	}
+	cmd := reproduceCommand(pkg, test, flags)
+	result.Properties["reproduce"] = cmd
+	if flags.empty() {
+		return ""
+	}
+	if flags.Shuffle != "" {
+		result.Properties["shuffle"] = flags.Shuffle
+	}
+	if flags.Count > 1 {
+		result.Properties["count"] = flags.Count
+	}
+	if flags.Runs > 1 {
+		result.Properties["runs"] = flags.Runs
+	}
+	if flags.Race {
+		result.Properties["race"] = true
+	}
+	if flags.Timeout != "" {
+		result.Properties["timeout"] = flags.Timeout
+	}
+	if len(flags.CPU) > 0 {
+		result.Properties["cpu"] = flags.CPU
+	}
+	return cmd
+}
+
+// appendReproduce gives the command cmd reproducing result at the end of
+// its message.
+func appendReproduce(result *sarif.Result, cmd string) {
+	result.Message += "\n\nReproduce with: " + cmd
+	if result.Markdown != "" {
+		result.Markdown = fmt.Sprintf("%s\n\nReproduce with:\n\n```sh\n%s\n```", result.Markdown, cmd)
+	}
+}
@@ -0,0 +1,128 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/testjson"
+)
+
+func TestCollectRunFlags(t *testing.T) {
+	events := []testjson.TestEvent{
+		{Action: "start", Package: testPkg},
+		{Action: "output", Package: testPkg, Output: "-test.shuffle 1\n"},
+		{Action: "start", Package: testPkg},
+		{Action: "output", Package: testPkg, Output: "-test.shuffle 1792354956937405435\n"},
+	}
+	events = append(events, runEvents("TestA", "pass")...)
+	events = append(events, runEvents("TestA", "fail", "==================\n", "WARNING: DATA RACE\n", "    testing.go:1490: race detected during execution of test\n")...)
+	events = append(events, testOutput("BenchmarkX", "BenchmarkX   \t 100\t 10 ns/op\n", "BenchmarkX-2 \t 100\t 6 ns/op\n")...)
+	events = append(events, pkgOutput("panic: test timed out after 30s\n")...)
+	events = append(events, runEvents("TestOther", "pass")...)
+	events = append(events, testjson.TestEvent{Action: "output", Package: "example.com/app/bar", Output: "ok\n"})
+
+	got := collectRunFlags(events)
+	want := map[string]runFlags{
+		testPkg: {
+			Shuffle: "1792354956937405435",
+			Race:    true,
+			Timeout: "30s",
+			CPU:     []int{1, 2},
+		},
+		"example.com/app/bar": {},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("collectRunFlags() = %+v, want %+v", got, want)
+	}
+	if !got["example.com/app/bar"].empty() {
+		t.Error("flags of a plain run are not empty")
+	}
+}
+
+func TestCollectRunFlags_Repeats(t *testing.T) {
+	// -count=2 and -cpu=1,2 print the same events for tests, so repeated
+	// runs give the count only when benchmarks show the CPU values.
+	var tests []testjson.TestEvent
+	for range 2 {
+		tests = append(tests, runEvents("TestA", "pass")...)
+	}
+	var benchmarks []testjson.TestEvent
+	for range 4 {
+		benchmarks = append(benchmarks, runEvents("TestA", "pass")...)
+	}
+	benchmarks = append(benchmarks, testOutput("BenchmarkX", "BenchmarkX   \t 100\t 10 ns/op\n", "BenchmarkX-2 \t 100\t 6 ns/op\n")...)
+
+	if got, want := collectRunFlags(tests)[testPkg], (runFlags{Runs: 2}); !reflect.DeepEqual(got, want) {
+		t.Errorf("flags of repeated tests = %+v, want %+v", got, want)
+	}
+	if got := reproduceCommand(testPkg, "TestA", collectRunFlags(tests)[testPkg]); got != "go test -run '^TestA$' example.com/app/foo" {
+		t.Errorf("command = %s, want no -count", got)
+	}
+	if got, want := collectRunFlags(benchmarks)[testPkg], (runFlags{Count: 2, CPU: []int{1, 2}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("flags with benchmarks = %+v, want %+v", got, want)
+	}
+}
+
+func TestReproduceCommand(t *testing.T) {
+	tests := []struct {
+		pkg, test string
+		flags     runFlags
+		want      string
+	}{
+		{testPkg, "", runFlags{Shuffle: "42"}, "go test -shuffle=42 example.com/app/foo"},
+		{
+			testPkg, "TestA/case_1.x", runFlags{Count: 3, Race: true, CPU: []int{1, 4}},
+			`go test -run '^TestA$/^case_1\.x$' -count=3 -race -cpu=1,4 example.com/app/foo`,
+		},
+		{testPkg, "TestA/it's", runFlags{Timeout: "1m0s"}, `go test -run '^TestA$/^it'\''s$' -timeout=1m0s example.com/app/foo`},
+	}
+	for _, tt := range tests {
+		if got := reproduceCommand(tt.pkg, tt.test, tt.flags); got != tt.want {
+			t.Errorf("reproduceCommand(%q, %q) = %s, want %s", tt.pkg, tt.test, got, tt.want)
+		}
+	}
+}
+
+func TestBuildReport_RunFlags(t *testing.T) {
+	events := []testjson.TestEvent{
+		{Action: "start", Package: testPkg},
+		{Action: "output", Package: testPkg, Output: "-test.shuffle 1699\n"},
+	}
+	events = append(events, runEvents("TestOrder", "fail", "    foo_test.go:3: order\n")...)
+	events = append(events, pkgOutput("FAIL\n")...)
+	events = append(events, testjson.TestEvent{Action: "fail", Package: testPkg})
+	in := input{Path: "test.json", Events: events}
+
+	results := buildReport([]input{in}, ConvertOptions{}).Results
+	if len(results) == 0 {
+		t.Fatal("no results")
+	}
+	for _, r := range results {
+		if r.Properties["shuffle"] != "1699" {
+			t.Errorf("%s: properties = %v, want the shuffle seed", r.Location.Function, r.Properties)
+		}
+		if r.Location.Function == "TestOrder" && !strings.HasSuffix(r.Message, "\n\nReproduce with: go test -run '^TestOrder$' -shuffle=1699 example.com/app/foo") {
+			t.Errorf("message = %q, want a reproduction command", r.Message)
+		}
+	}
+}
+
+func TestBuildReport_RunFlagsMergeShards(t *testing.T) {
+	shard := func(seed string) input {
+		events := []testjson.TestEvent{
+			{Action: "start", Package: testPkg},
+			{Action: "output", Package: testPkg, Output: "-test.shuffle " + seed + "\n"},
+		}
+		events = append(events, runEvents("TestOrder", "fail", "    foo_test.go:3: order\n")...)
+		return input{Path: "shard" + seed + ".json", Events: events}
+	}
+
+	results := buildReport([]input{shard("1"), shard("2")}, ConvertOptions{}).Results
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want the failure merged across shards", len(results))
+	}
+	if got := results[0].Properties["inputs"]; !reflect.DeepEqual(got, []string{"shard1.json", "shard2.json"}) {
+		t.Errorf("inputs = %v", got)
+	}
+}
@@ -11,6 +11,10 @@ type Report struct {
 	Rules []Rule
 	// Results contains the actual findings/test failures.
 	Results []Result
+	// Artifacts lists files referenced by the results.
+	Artifacts []Artifact
+	// Properties holds additional key/value data describing the run.
+	Properties map[string]any
 }
 
 // Rule defines a rule that can be violated.
@@ -19,6 +23,10 @@ type Rule struct {
 	ID string
 	// Description explains what this rule checks.
 	Description string
+	// HelpURI links to the documentation of the rule, if any.
+	HelpURI string
+	// Tags classify the results of the rule, such as by area or owner.
+	Tags []string
 }
 
 // Result represents a single finding.
@@ -29,8 +37,115 @@ type Result struct {
 	Level string
 	// Message describes the specific issue found.
 	Message string
+	// Markdown is an optional Markdown rendering of Message.
+	Markdown string
 	// Location identifies where the issue was found.
 	Location *LogicalLocation
+	// PhysicalLocation identifies the source region of the issue, if known.
+	PhysicalLocation *PhysicalLocation
+	// RelatedLocations are further regions relevant to the result.
+	RelatedLocations []RelatedLocation
+	// Properties holds additional key/value data attached to the result.
+	Properties map[string]any
+	// Attachments lists files that provide evidence for the result.
+	Attachments []Attachment
+	// Stacks holds call stacks relevant to the result, such as goroutines.
+	Stacks []Stack
+	// Fixes are proposed changes that resolve the result.
+	Fixes []Fix
+}
+
+// RelatedLocation is a region relevant to a result besides its location.
+type RelatedLocation struct {
+	// Message explains why the region is relevant.
+	Message string
+	// Location identifies the region.
+	Location PhysicalLocation
+}
+
+// Fix is a proposed change that resolves a result.
+type Fix struct {
+	// Description explains the change.
+	Description string
+	// Changes are the edits to make, one entry per file.
+	Changes []ArtifactChange
+}
+
+// ArtifactChange is a set of edits to a single file.
+type ArtifactChange struct {
+	// URI locates the file to change.
+	URI string
+	// Replacements are the edits, in order of their position in the file.
+	Replacements []Replacement
+}
+
+// Replacement replaces a region of a file with new text. The region is
+// given by lines and columns when StartLine is set, and by byte offsets
+// otherwise.
+type Replacement struct {
+	// StartLine, StartColumn, EndLine and EndColumn delimit the deleted
+	// region, as in PhysicalLocation.
+	StartLine, StartColumn, EndLine, EndColumn int
+	// ByteOffset is the 0-based offset of the deleted region.
+	ByteOffset int
+	// ByteLength is the length of the deleted region; 0 inserts text.
+	ByteLength int
+	// InsertedText is the text to insert in place of the region.
+	InsertedText string
+}
+
+// Stack is a call stack, innermost frame first.
+type Stack struct {
+	// Message describes the stack, such as the goroutine and its state.
+	Message string
+	// Frames are the calls on the stack.
+	Frames []StackFrame
+}
+
+// StackFrame is a single call on a Stack.
+type StackFrame struct {
+	// Function is the fully qualified function name.
+	Function string
+	// Module is the package containing the function.
+	Module string
+	// URI locates the source file of the call.
+	URI string
+	// Line is the 1-based line of the call, or 0 if unknown.
+	Line int
+	// ThreadID identifies the goroutine executing the frame.
+	ThreadID int
+}
+
+// Attachment is a file relevant to a result, such as a screenshot.
+type Attachment struct {
+	// URI locates the attached file.
+	URI string
+	// Description explains what the file contains.
+	Description string
+}
+
+// Artifact describes a file referenced by the report.
+type Artifact struct {
+	// URI locates the file.
+	URI string
+	// Length is the file size in bytes.
+	Length int64
+	// SHA256 is the hex-encoded SHA-256 digest of the file contents.
+	SHA256 string
+}
+
+// PhysicalLocation identifies a region of a file.
+type PhysicalLocation struct {
+	// URI locates the file, relative to the source root when possible.
+	URI string
+	// StartLine is the 1-based first line, or 0 to refer to the whole file.
+	StartLine int
+	// StartColumn is the 1-based first column, or 0 if unknown.
+	StartColumn int
+	// EndLine is the 1-based last line, or 0 if the same as StartLine.
+	EndLine int
+	// EndColumn is the 1-based column after the region, or 0 if unknown.
+	EndColumn int
 }
 
 // LogicalLocation identifies where an issue occurred without file coordinates.
@@ -10,8 +10,10 @@ type sarifDoc struct {
 }
 
 type run struct {
-	Tool    tool     `json:"tool"`
-	Results []result `json:"results"`
+	Tool       tool           `json:"tool"`
+	Results    []result       `json:"results"`
+	Artifacts  []artifact     `json:"artifacts,omitempty"`
+	Properties map[string]any `json:"properties,omitempty"`
 }
 
 type tool struct {
@@ -25,21 +27,100 @@ type driver struct {
 }
 
 type rule struct {
-	ID               string  `json:"id"`
-	ShortDescription message `json:"shortDescription,omitempty"`
+	ID               string          `json:"id"`
+	ShortDescription message         `json:"shortDescription,omitempty"`
+	HelpURI          string          `json:"helpUri,omitempty"`
+	Properties       *ruleProperties `json:"properties,omitempty"`
+}
+
+type ruleProperties struct {
+	Tags []string `json:"tags"`
 }
 
 type result struct {
 	RuleID           string            `json:"ruleId"`
 	Level            string            `json:"level"`
 	Message          message           `json:"message"`
+	Locations        []location        `json:"locations,omitempty"`
 	LogicalLocations []logicalLocation `json:"logicalLocations,omitempty"`
+	RelatedLocations []location        `json:"relatedLocations,omitempty"`
+	Attachments      []attachment      `json:"attachments,omitempty"`
+	Stacks           []stack           `json:"stacks,omitempty"`
+	Fixes            []fix             `json:"fixes,omitempty"`
+	Properties       map[string]any    `json:"properties,omitempty"`
 }
 
 type message struct {
+	Text     string `json:"text"`
+	Markdown string `json:"markdown,omitempty"`
+}
+
+type location struct {
+	ID               *int              `json:"id,omitempty"`
+	Message          *message          `json:"message,omitempty"`
+	PhysicalLocation *physicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []logicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type stack struct {
+	Message *message     `json:"message,omitempty"`
+	Frames  []stackFrame `json:"frames"`
+}
+
+type stackFrame struct {
+	Location *location `json:"location,omitempty"`
+	Module   string    `json:"module,omitempty"`
+	ThreadID int       `json:"threadId,omitempty"`
+}
+
+type physicalLocation struct {
+	ArtifactLocation artifactLocation `json:"artifactLocation"`
+	Region           *region          `json:"region,omitempty"`
+}
+
+type region struct {
+	StartLine   int  `json:"startLine,omitempty"`
+	StartColumn int  `json:"startColumn,omitempty"`
+	EndLine     int  `json:"endLine,omitempty"`
+	EndColumn   int  `json:"endColumn,omitempty"`
+	ByteOffset  *int `json:"byteOffset,omitempty"`
+	ByteLength  *int `json:"byteLength,omitempty"`
+}
+
+type fix struct {
+	Description     *message         `json:"description,omitempty"`
+	ArtifactChanges []artifactChange `json:"artifactChanges"`
+}
+
+type artifactChange struct {
+	ArtifactLocation artifactLocation `json:"artifactLocation"`
+	Replacements     []replacement    `json:"replacements"`
+}
+
+type replacement struct {
+	DeletedRegion   region           `json:"deletedRegion"`
+	InsertedContent *artifactContent `json:"insertedContent,omitempty"`
+}
+
+type artifactContent struct {
 	Text string `json:"text"`
 }
 
+type artifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type attachment struct {
+	Description      *message         `json:"description,omitempty"`
+	ArtifactLocation artifactLocation `json:"artifactLocation"`
+}
+
+type artifact struct {
+	Location artifactLocation  `json:"location"`
+	Length   int64             `json:"length,omitempty"`
+	Hashes   map[string]string `json:"hashes,omitempty"`
+}
+
 type logicalLocation struct {
 	FullyQualifiedName string `json:"fullyQualifiedName,omitempty"`
 	Kind               string `json:"kind,omitempty"`
@@ -63,21 +144,28 @@ func buildRun(r *Report) run {
 				InformationURI: r.ToolInfoURI,
 			},
 		},
-		Results: make([]result, 0, len(r.Results)),
+		Results:    make([]result, 0, len(r.Results)),
+		Properties: r.Properties,
 	}
 
 	for _, rl := range r.Rules {
-		rn.Tool.Driver.Rules = append(rn.Tool.Driver.Rules, rule{
+		ru := rule{
 			ID:               rl.ID,
 			ShortDescription: message{Text: rl.Description},
-		})
+			HelpURI:          rl.HelpURI,
+		}
+		if len(rl.Tags) > 0 {
+			ru.Properties = &ruleProperties{Tags: rl.Tags}
+		}
+		rn.Tool.Driver.Rules = append(rn.Tool.Driver.Rules, ru)
 	}
 
 	for _, res := range r.Results {
 		r := result{
-			RuleID:  res.RuleID,
-			Level:   res.Level,
-			Message: message{Text: res.Message},
+			RuleID:     res.RuleID,
+			Level:      res.Level,
+			Message:    message{Text: res.Message, Markdown: res.Markdown},
+			Properties: res.Properties,
 		}
 
 		if res.Location != nil {
@@ -100,8 +188,107 @@ func buildRun(r *Report) run {
 			}
 		}
 
+		if res.PhysicalLocation != nil {
+			r.Locations = []location{{PhysicalLocation: buildPhysicalLocation(res.PhysicalLocation)}}
+		}
+
+		for i, rl := range res.RelatedLocations {
+			loc := location{ID: &i, PhysicalLocation: buildPhysicalLocation(&rl.Location)}
+			if rl.Message != "" {
+				loc.Message = &message{Text: rl.Message}
+			}
+			r.RelatedLocations = append(r.RelatedLocations, loc)
+		}
+
+		for _, a := range res.Attachments {
+			att := attachment{ArtifactLocation: artifactLocation{URI: a.URI}}
+			if a.Description != "" {
+				att.Description = &message{Text: a.Description}
+			}
+			r.Attachments = append(r.Attachments, att)
+		}
+
+		for _, st := range res.Stacks {
+			r.Stacks = append(r.Stacks, buildStack(st))
+		}
+
+		for _, f := range res.Fixes {
+			r.Fixes = append(r.Fixes, buildFix(f))
+		}
+
 		rn.Results = append(rn.Results, r)
 	}
 
+	for _, a := range r.Artifacts {
+		art := artifact{Location: artifactLocation{URI: a.URI}, Length: a.Length}
+		if a.SHA256 != "" {
+			art.Hashes = map[string]string{"sha-256": a.SHA256}
+		}
+		rn.Artifacts = append(rn.Artifacts, art)
+	}
+
 	return rn
 }
+
+func buildPhysicalLocation(pl *PhysicalLocation) *physicalLocation {
+	loc := &physicalLocation{ArtifactLocation: artifactLocation{URI: pl.URI}}
+	if pl.StartLine > 0 {
+		loc.Region = &region{
+			StartLine:   pl.StartLine,
+			StartColumn: pl.StartColumn,
+			EndLine:     pl.EndLine,
+			EndColumn:   pl.EndColumn,
+		}
+	}
+	return loc
+}
+
+func buildStack(st Stack) stack {
+	s := stack{Frames: make([]stackFrame, 0, len(st.Frames))}
+	if st.Message != "" {
+		s.Message = &message{Text: st.Message}
+	}
+	for _, f := range st.Frames {
+		loc := &location{}
+		if f.URI != "" {
+			loc.PhysicalLocation = buildPhysicalLocation(&PhysicalLocation{URI: f.URI, StartLine: f.Line})
+		}
+		if f.Function != "" {
+			loc.LogicalLocations = []logicalLocation{{FullyQualifiedName: f.Function, Kind: "function"}}
+		}
+		s.Frames = append(s.Frames, stackFrame{Location: loc, Module: f.Module, ThreadID: f.ThreadID})
+	}
+	return s
+}
+
+func buildFix(f Fix) fix {
+	fx := fix{ArtifactChanges: make([]artifactChange, 0, len(f.Changes))}
+	if f.Description != "" {
+		fx.Description = &message{Text: f.Description}
+	}
+	for _, c := range f.Changes {
+		ac := artifactChange{
+			ArtifactLocation: artifactLocation{URI: c.URI},
+			Replacements:     make([]replacement, 0, len(c.Replacements)),
+		}
+		for _, rp := range c.Replacements {
+			rep := replacement{}
+			if rp.StartLine > 0 {
+				rep.DeletedRegion = region{
+					StartLine:   rp.StartLine,
+					StartColumn: rp.StartColumn,
+					EndLine:     rp.EndLine,
+					EndColumn:   rp.EndColumn,
+				}
+			} else {
+				rep.DeletedRegion = region{ByteOffset: &rp.ByteOffset, ByteLength: &rp.ByteLength}
+			}
+			if rp.InsertedText != "" {
+				rep.InsertedContent = &artifactContent{Text: rp.InsertedText}
+			}
+			ac.Replacements = append(ac.Replacements, rep)
+		}
+		fx.ArtifactChanges = append(fx.ArtifactChanges, ac)
+	}
+	return fx
+}
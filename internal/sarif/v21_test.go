@@ -125,3 +125,387 @@ func TestSerializeV21_LogicalLocation(t *testing.T) {
 		t.Errorf("fullyQualifiedName = %v, want %v", loc["fullyQualifiedName"], "example.com/foo.TestBar")
 	}
 }
+
+func TestSerializeV21_RunProperties(t *testing.T) {
+	report := &Report{
+		ToolName:   "go-test-sarif",
+		Properties: map[string]any{"coverage": map[string]float64{"example.com/foo": 73.2}},
+	}
+
+	data, err := Serialize(report, Version210, false)
+	if err != nil {
+		t.Fatalf("Serialize returned error: %v", err)
+	}
+
+	var doc struct {
+		Runs []struct {
+			Properties struct {
+				Coverage map[string]float64 `json:"coverage"`
+			} `json:"properties"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if got := doc.Runs[0].Properties.Coverage["example.com/foo"]; got != 73.2 {
+		t.Errorf("coverage = %v, want 73.2", got)
+	}
+}
+
+func TestSerializeV21_Attachments(t *testing.T) {
+	report := &Report{
+		ToolName: "go-test-sarif",
+		Results: []Result{
+			{
+				RuleID:      testRuleID,
+				Level:       testLevelError,
+				Message:     "TestBar failed",
+				Attachments: []Attachment{{URI: "artifacts/shot.png", Description: "screenshot"}},
+			},
+		},
+		Artifacts: []Artifact{{URI: "artifacts/shot.png", Length: 3, SHA256: "abc"}},
+	}
+
+	data, err := Serialize(report, Version210, false)
+	if err != nil {
+		t.Fatalf("Serialize returned error: %v", err)
+	}
+
+	var doc struct {
+		Runs []struct {
+			Results []struct {
+				Attachments []struct {
+					Description      struct{ Text string } `json:"description"`
+					ArtifactLocation struct{ URI string }  `json:"artifactLocation"`
+				} `json:"attachments"`
+			} `json:"results"`
+			Artifacts []struct {
+				Location struct{ URI string } `json:"location"`
+				Length   int64                `json:"length"`
+				Hashes   map[string]string    `json:"hashes"`
+			} `json:"artifacts"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+
+	att := doc.Runs[0].Results[0].Attachments
+	if len(att) != 1 || att[0].ArtifactLocation.URI != "artifacts/shot.png" || att[0].Description.Text != "screenshot" {
+		t.Errorf("attachments = %+v", att)
+	}
+	arts := doc.Runs[0].Artifacts
+	if len(arts) != 1 || arts[0].Hashes["sha-256"] != "abc" || arts[0].Length != 3 {
+		t.Errorf("artifacts = %+v", arts)
+	}
+}
+
+func TestSerializeV21_PhysicalLocation(t *testing.T) {
+	report := &Report{
+		ToolName: "go-test-sarif",
+		Results: []Result{
+			{
+				RuleID:           testRuleID,
+				Level:            testLevelError,
+				Message:          "TestBar failed",
+				PhysicalLocation: &PhysicalLocation{URI: "foo/bar_test.go", StartLine: 12, StartColumn: 3},
+			},
+			{
+				RuleID:           testRuleID,
+				Level:            testLevelError,
+				Message:          "whole file",
+				PhysicalLocation: &PhysicalLocation{URI: "foo/bar.go"},
+			},
+		},
+	}
+
+	data, err := Serialize(report, Version210, false)
+	if err != nil {
+		t.Fatalf("Serialize returned error: %v", err)
+	}
+
+	var doc struct {
+		Runs []struct {
+			Results []struct {
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct{ URI string } `json:"artifactLocation"`
+						Region           *struct {
+							StartLine   int `json:"startLine"`
+							StartColumn int `json:"startColumn"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+
+	loc := doc.Runs[0].Results[0].Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "foo/bar_test.go" {
+		t.Errorf("uri = %q, want %q", loc.ArtifactLocation.URI, "foo/bar_test.go")
+	}
+	if loc.Region == nil || loc.Region.StartLine != 12 || loc.Region.StartColumn != 3 {
+		t.Errorf("region = %+v, want 12:3", loc.Region)
+	}
+	if region := doc.Runs[0].Results[1].Locations[0].PhysicalLocation.Region; region != nil {
+		t.Errorf("region = %+v, want none for a whole-file location", region)
+	}
+}
+
+func TestSerializeV21_RelatedLocations(t *testing.T) {
+	report := &Report{
+		ToolName: "go-test-sarif",
+		Results: []Result{
+			{
+				RuleID:           testRuleID,
+				Level:            "warning",
+				Message:          "low coverage",
+				PhysicalLocation: &PhysicalLocation{URI: "foo/bar.go"},
+				RelatedLocations: []RelatedLocation{
+					{Message: "not covered", Location: PhysicalLocation{URI: "foo/bar.go", StartLine: 3, EndLine: 5}},
+					{Location: PhysicalLocation{URI: "foo/bar.go", StartLine: 9}},
+				},
+			},
+		},
+	}
+
+	data, err := Serialize(report, Version210, false)
+	if err != nil {
+		t.Fatalf("Serialize returned error: %v", err)
+	}
+
+	var doc struct {
+		Runs []struct {
+			Results []struct {
+				RelatedLocations []struct {
+					ID               *int                   `json:"id"`
+					Message          *struct{ Text string } `json:"message"`
+					PhysicalLocation struct {
+						ArtifactLocation struct{ URI string } `json:"artifactLocation"`
+						Region           struct {
+							StartLine int `json:"startLine"`
+							EndLine   int `json:"endLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"relatedLocations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+
+	rel := doc.Runs[0].Results[0].RelatedLocations
+	if len(rel) != 2 {
+		t.Fatalf("relatedLocations = %+v, want 2 entries", rel)
+	}
+	if rel[0].ID == nil || *rel[0].ID != 0 || rel[1].ID == nil || *rel[1].ID != 1 {
+		t.Errorf("ids = %v, %v, want 0 and 1", rel[0].ID, rel[1].ID)
+	}
+	if rel[0].Message == nil || rel[0].Message.Text != "not covered" || rel[1].Message != nil {
+		t.Errorf("messages = %+v, %+v", rel[0].Message, rel[1].Message)
+	}
+	loc := rel[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "foo/bar.go" || loc.Region.StartLine != 3 || loc.Region.EndLine != 5 {
+		t.Errorf("location = %+v", loc)
+	}
+}
+
+func TestSerializeV21_Stacks(t *testing.T) {
+	report := &Report{
+		ToolName: "go-test-sarif",
+		Results: []Result{
+			{
+				RuleID:  testRuleID,
+				Level:   testLevelError,
+				Message: "fatal error",
+				Stacks: []Stack{{
+					Message: "goroutine 7 [running]",
+					Frames: []StackFrame{{
+						Function: "example.com/foo.TestBar",
+						Module:   testModuleName,
+						URI:      "foo/bar_test.go",
+						Line:     12,
+						ThreadID: 7,
+					}},
+				}},
+			},
+		},
+	}
+
+	data, err := Serialize(report, Version210, false)
+	if err != nil {
+		t.Fatalf("Serialize returned error: %v", err)
+	}
+
+	var doc struct {
+		Runs []struct {
+			Results []struct {
+				Stacks []struct {
+					Message struct{ Text string } `json:"message"`
+					Frames  []struct {
+						Location struct {
+							PhysicalLocation struct {
+								ArtifactLocation struct{ URI string }    `json:"artifactLocation"`
+								Region           struct{ StartLine int } `json:"region"`
+							} `json:"physicalLocation"`
+							LogicalLocations []struct {
+								FullyQualifiedName string `json:"fullyQualifiedName"`
+							} `json:"logicalLocations"`
+						} `json:"location"`
+						Module   string `json:"module"`
+						ThreadID int    `json:"threadId"`
+					} `json:"frames"`
+				} `json:"stacks"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+
+	st := doc.Runs[0].Results[0].Stacks
+	if len(st) != 1 || st[0].Message.Text != "goroutine 7 [running]" || len(st[0].Frames) != 1 {
+		t.Fatalf("stacks = %+v", st)
+	}
+	f := st[0].Frames[0]
+	if f.Location.PhysicalLocation.ArtifactLocation.URI != "foo/bar_test.go" || f.Location.PhysicalLocation.Region.StartLine != 12 {
+		t.Errorf("frame location = %+v", f.Location.PhysicalLocation)
+	}
+	if f.Location.LogicalLocations[0].FullyQualifiedName != "example.com/foo.TestBar" {
+		t.Errorf("frame function = %+v", f.Location.LogicalLocations)
+	}
+	if f.Module != testModuleName || f.ThreadID != 7 {
+		t.Errorf("frame module = %q, threadId = %d", f.Module, f.ThreadID)
+	}
+}
+
+func TestSerializeV21_Markdown(t *testing.T) {
+	report := &Report{
+		ToolName: "go-test-sarif",
+		Results: []Result{
+			{RuleID: testRuleID, Level: testLevelError, Message: "plain", Markdown: "**rich**"},
+		},
+	}
+
+	data, err := Serialize(report, Version210, false)
+	if err != nil {
+		t.Fatalf("Serialize returned error: %v", err)
+	}
+
+	var doc struct {
+		Runs []struct {
+			Results []struct {
+				Message struct {
+					Text     string `json:"text"`
+					Markdown string `json:"markdown"`
+				} `json:"message"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+
+	msg := doc.Runs[0].Results[0].Message
+	if msg.Text != "plain" || msg.Markdown != "**rich**" {
+		t.Errorf("message = %+v, want text and markdown", msg)
+	}
+}
+
+func TestSerializeV21_Fixes(t *testing.T) {
+	report := &Report{
+		ToolName: "go-test-sarif",
+		Rules:    []Rule{{ID: testRuleID, Description: "d", HelpURI: "https://example.com/rule", Tags: []string{"db"}}},
+		Results: []Result{
+			{
+				RuleID:  testRuleID,
+				Level:   testLevelError,
+				Message: "fixable",
+				Fixes: []Fix{{
+					Description: "Insert format string",
+					Changes: []ArtifactChange{{
+						URI: "foo/foo.go",
+						Replacements: []Replacement{
+							{ByteOffset: 0, InsertedText: `"%s", `},
+							{StartLine: 3, StartColumn: 1, EndLine: 4, EndColumn: 1},
+						},
+					}},
+				}},
+			},
+		},
+	}
+
+	data, err := Serialize(report, Version210, false)
+	if err != nil {
+		t.Fatalf("Serialize returned error: %v", err)
+	}
+
+	type region struct {
+		StartLine  int  `json:"startLine"`
+		ByteOffset *int `json:"byteOffset"`
+		ByteLength *int `json:"byteLength"`
+	}
+	var doc struct {
+		Runs []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						HelpURI    string `json:"helpUri"`
+						Properties struct {
+							Tags []string `json:"tags"`
+						} `json:"properties"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				Fixes []struct {
+					Description struct {
+						Text string `json:"text"`
+					} `json:"description"`
+					ArtifactChanges []struct {
+						ArtifactLocation struct{ URI string } `json:"artifactLocation"`
+						Replacements     []struct {
+							DeletedRegion   region `json:"deletedRegion"`
+							InsertedContent *struct {
+								Text string `json:"text"`
+							} `json:"insertedContent"`
+						} `json:"replacements"`
+					} `json:"artifactChanges"`
+				} `json:"fixes"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+
+	if got := doc.Runs[0].Tool.Driver.Rules[0].HelpURI; got != "https://example.com/rule" {
+		t.Errorf("helpUri = %q", got)
+	}
+	if got := doc.Runs[0].Tool.Driver.Rules[0].Properties.Tags; len(got) != 1 || got[0] != "db" {
+		t.Errorf("rule tags = %v, want [db]", got)
+	}
+	fixes := doc.Runs[0].Results[0].Fixes
+	if len(fixes) != 1 || fixes[0].Description.Text != "Insert format string" {
+		t.Fatalf("fixes = %+v", fixes)
+	}
+	change := fixes[0].ArtifactChanges[0]
+	if change.ArtifactLocation.URI != "foo/foo.go" || len(change.Replacements) != 2 {
+		t.Fatalf("artifactChanges = %+v", change)
+	}
+	insert := change.Replacements[0]
+	if r := insert.DeletedRegion; r.ByteOffset == nil || *r.ByteOffset != 0 || r.ByteLength == nil || *r.ByteLength != 0 {
+		t.Errorf("deletedRegion = %+v, want an empty region at offset 0", r)
+	}
+	if insert.InsertedContent == nil || insert.InsertedContent.Text != `"%s", ` {
+		t.Errorf("insertedContent = %+v", insert.InsertedContent)
+	}
+	deletion := change.Replacements[1]
+	if deletion.DeletedRegion.StartLine != 3 || deletion.DeletedRegion.ByteOffset != nil || deletion.InsertedContent != nil {
+		t.Errorf("replacement = %+v, want a line-based deletion", deletion)
+	}
+}
@@ -0,0 +1,274 @@
+package internal
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
+	"github.com/ivuorinen/go-test-sarif-action/internal/source"
+)
+
+// slowRule is reported for tests that run longer than allowed.
+var slowRule = sarif.Rule{
+	ID:          "go-test-slow",
+	Description: "slow test",
+}
+
+// SlowOptions selects the tests reported as slow. Tests slower than their
+// threshold are reported as warnings and the Top slowest others as notes.
+// The zero value reports none.
+type SlowOptions struct {
+	// Threshold is the time a test may take. Zero disables it.
+	Threshold time.Duration
+	// Overrides replace Threshold for matching tests. The last matching
+	// override applies.
+	Overrides []SlowOverride
+	// Top reports the given number of slowest tests.
+	Top int
+}
+
+// SlowOverride sets the threshold of the tests matched by its patterns,
+// which follow path.Match. Empty patterns match everything.
+type SlowOverride struct {
+	// Package matches the import path of the package.
+	Package string
+	// Test matches the test name, including subtest names.
+	Test string
+	// Threshold is the time matching tests may take. Zero exempts them.
+	Threshold time.Duration
+}
+
+// enabled reports whether any test can be reported.
+func (o SlowOptions) enabled() bool {
+	return o.Threshold > 0 || o.Top > 0 || len(o.Overrides) > 0
+}
+
+// threshold returns the threshold of test in pkg.
+func (o SlowOptions) threshold(pkg, test string) time.Duration {
+	threshold := o.Threshold
+	for _, ov := range o.Overrides {
+		if globMatch(ov.Package, pkg) && globMatch(ov.Test, test) {
+			threshold = ov.Threshold
+		}
+	}
+	return threshold
+}
+
+// globMatch matches name against pattern, treating an empty or malformed
+// pattern as matching everything or nothing respectively.
+func globMatch(pattern, name string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+// slowConfig is the JSON form of SlowOptions, with durations as strings
+// such as "1.5s".
+type slowConfig struct {
+	Threshold string `json:"threshold"`
+	Top       int    `json:"top"`
+	Overrides []struct {
+		Package   string `json:"package"`
+		Test      string `json:"test"`
+		Threshold string `json:"threshold"`
+	} `json:"overrides"`
+}
+
+// LoadSlowOptions reads slow test options from a JSON file:
+//
+//	{
+//	  "threshold": "1s",
+//	  "top": 10,
+//	  "overrides": [
+//	    {"package": "example.com/app/db", "threshold": "10s"},
+//	    {"test": "TestGolden/*", "threshold": "0"}
+//	  ]
+//	}
+func LoadSlowOptions(file string) (SlowOptions, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return SlowOptions{}, err
+	}
+	var cfg slowConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return SlowOptions{}, fmt.Errorf("%s: %w", file, err)
+	}
+
+	opts := SlowOptions{Top: cfg.Top}
+	if opts.Threshold, err = parseThreshold(cfg.Threshold); err != nil {
+		return SlowOptions{}, fmt.Errorf("%s: %w", file, err)
+	}
+	for _, ov := range cfg.Overrides {
+		for _, pattern := range []string{ov.Package, ov.Test} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return SlowOptions{}, fmt.Errorf("%s: invalid pattern %q", file, pattern)
+			}
+		}
+		threshold, err := parseThreshold(ov.Threshold)
+		if err != nil {
+			return SlowOptions{}, fmt.Errorf("%s: %w", file, err)
+		}
+		opts.Overrides = append(opts.Overrides, SlowOverride{Package: ov.Package, Test: ov.Test, Threshold: threshold})
+	}
+	return opts, nil
+}
+
+// parseThreshold parses a duration, treating "" as zero.
+func parseThreshold(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid threshold: %w", err)
+	}
+	return d, nil
+}
+
+// timing is the longest run of a test across all inputs.
+type timing struct {
+	testKey
+	// Elapsed is the duration of the longest run.
+	Elapsed time.Duration
+	// Inputs are the inputs that ran the test.
+	Inputs []string
+}
+
+// timings collects the durations of finished tests.
+type timings struct {
+	byKey map[testKey]*timing
+	order []*timing
+}
+
+// add records the finished tests of cases, read from the input at path.
+func (t *timings) add(cases []*testCase, path string) {
+	if t.byKey == nil {
+		t.byKey = make(map[testKey]*timing)
+	}
+	for _, c := range cases {
+		if c.test == "" || (c.Action != "pass" && c.Action != "fail") {
+			continue
+		}
+		// go test reports elapsed times in seconds, rounded to milliseconds.
+		elapsed := time.Duration(math.Round(c.Elapsed*1000)) * time.Millisecond
+		tm := t.byKey[c.testKey]
+		if tm == nil {
+			tm = &timing{testKey: c.testKey}
+			t.byKey[c.testKey] = tm
+			t.order = append(t.order, tm)
+		}
+		tm.Elapsed = max(tm.Elapsed, elapsed)
+		if !slices.Contains(tm.Inputs, path) {
+			tm.Inputs = append(tm.Inputs, path)
+		}
+	}
+}
+
+// withSubtests returns the tests that are parents of a test in set.
+func withSubtests(set map[testKey]bool) map[testKey]bool {
+	out := make(map[testKey]bool)
+	for k := range set {
+		for i := strings.LastIndexByte(k.test, '/'); i > 0; i = strings.LastIndexByte(k.test[:i], '/') {
+			parent := testKey{pkg: k.pkg, test: k.test[:i]}
+			if out[parent] {
+				// Its own parents were marked when it was.
+				break
+			}
+			out[parent] = true
+		}
+	}
+	return out
+}
+
+// addSlowResults reports the tests that exceeded their threshold and the
+// slowest others. A test is not reported when one of its subtests is, as
+// its time includes theirs.
+func addSlowResults(b *reportBuilder, t timings, opts SlowOptions, resolver *source.Resolver) {
+	if !opts.enabled() {
+		return
+	}
+
+	over := make(map[testKey]bool)
+	for _, tm := range t.order {
+		if threshold := opts.threshold(tm.pkg, tm.test); threshold > 0 && tm.Elapsed > threshold {
+			over[tm.testKey] = true
+		}
+	}
+
+	// Rank the tests without subtests of their own.
+	all := make(map[testKey]bool, len(t.order))
+	for _, tm := range t.order {
+		all[tm.testKey] = true
+	}
+	parents := withSubtests(all)
+	var ranked []*timing
+	for _, tm := range t.order {
+		if !parents[tm.testKey] {
+			ranked = append(ranked, tm)
+		}
+	}
+	slices.SortStableFunc(ranked, func(a, b *timing) int {
+		return cmp.Compare(b.Elapsed, a.Elapsed)
+	})
+	rank := make(map[testKey]int)
+	for i, tm := range ranked[:min(opts.Top, len(ranked))] {
+		rank[tm.testKey] = i + 1
+	}
+
+	overParents := withSubtests(over)
+	for _, tm := range t.order {
+		_, top := rank[tm.testKey]
+		if !over[tm.testKey] && !top {
+			continue
+		}
+		if over[tm.testKey] && overParents[tm.testKey] {
+			continue
+		}
+		b.addRule(slowRule)
+		result := slowResult(tm, opts.threshold(tm.pkg, tm.test), over[tm.testKey], rank[tm.testKey], resolver)
+		for _, path := range tm.Inputs {
+			b.add(result, path)
+		}
+	}
+}
+
+// slowResult reports a slow test. Tests over their threshold are warnings
+// and the slowest others, ranked by rank, notes.
+func slowResult(tm *timing, threshold time.Duration, over bool, rank int, resolver *source.Resolver) sarif.Result {
+	result := sarif.Result{
+		RuleID:   slowRule.ID,
+		Level:    "note",
+		Location: &sarif.LogicalLocation{Module: tm.pkg, Function: tm.test},
+		Properties: map[string]any{
+			"elapsed": tm.Elapsed.Seconds(),
+		},
+	}
+	if threshold > 0 {
+		result.Properties["threshold"] = threshold.Seconds()
+	}
+	if over {
+		result.Level = "warning"
+		result.Message = fmt.Sprintf("%s took %s, over its threshold of %s", tm.test, tm.Elapsed, threshold)
+	} else {
+		result.Message = fmt.Sprintf("%s took %s, the #%d slowest test", tm.test, tm.Elapsed, rank)
+	}
+	if rank > 0 {
+		result.Properties["rank"] = rank
+	}
+	if pos := findTestFunc(resolver, tm.pkg, tm.test); pos != nil {
+		result.PhysicalLocation = &sarif.PhysicalLocation{
+			URI:       resolver.URI(pos.File),
+			StartLine: pos.Line,
+		}
+	}
+	return result
+}
@@ -0,0 +1,170 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/testjson"
+)
+
+// timedRun returns the events of a passed test that took elapsed seconds.
+func timedRun(pkg, test string, elapsed float64) []testjson.TestEvent {
+	return []testjson.TestEvent{
+		{Action: "run", Package: pkg, Test: test},
+		{Action: "pass", Package: pkg, Test: test, Elapsed: elapsed},
+	}
+}
+
+func TestAddSlowResults(t *testing.T) {
+	resolver := writeTestModule(t, map[string]string{
+		"foo/foo_test.go": "package foo\n\nimport \"testing\"\n\nfunc TestSlow(t *testing.T) {}\n",
+	})
+	var events []testjson.TestEvent
+	events = append(events, timedRun(testPkg, "TestSlow", 3.2)...)
+	events = append(events, timedRun(testPkg, "TestSlow", 2.5)...)
+	events = append(events, timedRun(testPkg, "TestTable", 4)...)
+	events = append(events, timedRun(testPkg, "TestTable/big", 3.9)...)
+	events = append(events, timedRun(testPkg, "TestTable/small", 0.1)...)
+	events = append(events, timedRun(testPkg, "TestFast", 0.5)...)
+	events = append(events, timedRun("example.com/app/db", "TestMigrate", 8)...)
+	events = append(events, testjson.TestEvent{Action: "pass", Package: testPkg, Elapsed: 9})
+	in := input{Path: "slow.json", Events: events}
+
+	opts := ConvertOptions{
+		SourceRoot: resolver.Root(),
+		Slow: SlowOptions{
+			Threshold: time.Second,
+			Overrides: []SlowOverride{{Package: "example.com/app/db", Threshold: 10 * time.Second}},
+			Top:       4,
+		},
+	}
+	results := buildReport([]input{in}, opts).Results
+
+	got := make(map[string]string)
+	for _, r := range results {
+		if r.RuleID != slowRule.ID {
+			t.Errorf("unexpected result %+v", r)
+			continue
+		}
+		got[r.Location.Function] = r.Level + ": " + r.Message
+	}
+	want := map[string]string{
+		"TestSlow":      "warning: TestSlow took 3.2s, over its threshold of 1s",
+		"TestTable/big": "warning: TestTable/big took 3.9s, over its threshold of 1s",
+		"TestMigrate":   "note: TestMigrate took 8s, the #1 slowest test",
+		"TestFast":      "note: TestFast took 500ms, the #4 slowest test",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("results = %v, want %v", got, want)
+	}
+
+	for _, r := range results {
+		if r.Location.Function != "TestSlow" {
+			continue
+		}
+		if r.Properties["elapsed"] != 3.2 || r.Properties["threshold"] != 1.0 || r.Properties["rank"] != 3 {
+			t.Errorf("properties = %v", r.Properties)
+		}
+		if r.PhysicalLocation == nil || r.PhysicalLocation.URI != "foo/foo_test.go" || r.PhysicalLocation.StartLine != 5 {
+			t.Errorf("location = %+v, want the test function", r.PhysicalLocation)
+		}
+	}
+}
+
+func TestAddSlowResults_Disabled(t *testing.T) {
+	in := input{Path: "slow.json", Events: timedRun(testPkg, "TestSlow", 100)}
+	if results := buildReport([]input{in}, ConvertOptions{}).Results; len(results) != 0 {
+		t.Errorf("results = %+v, want none", results)
+	}
+}
+
+func TestSlowOptions_Threshold(t *testing.T) {
+	opts := SlowOptions{
+		Threshold: time.Second,
+		Overrides: []SlowOverride{
+			{Package: "example.com/app/*", Threshold: 5 * time.Second},
+			{Test: "TestGolden/*"},
+		},
+	}
+	tests := []struct {
+		pkg, test string
+		want      time.Duration
+	}{
+		{"example.com/other", "TestA", time.Second},
+		{"example.com/app/db", "TestA", 5 * time.Second},
+		{"example.com/app/db/sub", "TestA", time.Second},
+		{"example.com/app/db", "TestGolden/x", 0},
+		{"example.com/app/db", "TestGolden", 5 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := opts.threshold(tt.pkg, tt.test); got != tt.want {
+			t.Errorf("threshold(%s, %s) = %v, want %v", tt.pkg, tt.test, got, tt.want)
+		}
+	}
+}
+
+func TestLoadSlowOptions(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "slow.json")
+	config := `{
+		"threshold": "1.5s",
+		"top": 5,
+		"overrides": [
+			{"package": "example.com/app/db", "threshold": "10s"},
+			{"test": "TestGolden/*", "threshold": "0"}
+		]
+	}`
+	if err := os.WriteFile(file, []byte(config), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	got, err := LoadSlowOptions(file)
+	if err != nil {
+		t.Fatalf("LoadSlowOptions() error = %v", err)
+	}
+	want := SlowOptions{
+		Threshold: 1500 * time.Millisecond,
+		Top:       5,
+		Overrides: []SlowOverride{
+			{Package: "example.com/app/db", Threshold: 10 * time.Second},
+			{Test: "TestGolden/*"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadSlowOptions() = %+v, want %+v", got, want)
+	}
+
+	for name, content := range map[string]string{
+		"duration.json": `{"threshold": "fast"}`,
+		"pattern.json":  `{"overrides": [{"test": "Test[", "threshold": "1s"}]}`,
+		"syntax.json":   `{`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		if _, err := LoadSlowOptions(path); err == nil {
+			t.Errorf("LoadSlowOptions(%s) returned no error", name)
+		}
+	}
+}
+
+func TestWithSubtests(t *testing.T) {
+	set := map[testKey]bool{
+		{testPkg, "TestA/x/y"}: true,
+		{testPkg, "TestA/z"}:   true,
+		{testPkg, "TestB"}:     true,
+		{"other", "TestA/x"}:   true,
+	}
+	want := map[testKey]bool{
+		{testPkg, "TestA"}:   true,
+		{testPkg, "TestA/x"}: true,
+		{"other", "TestA"}:   true,
+	}
+	if got := withSubtests(set); !reflect.DeepEqual(got, want) {
+		t.Errorf("withSubtests() = %v, want %v", got, want)
+	}
+}
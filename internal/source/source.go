@@ -0,0 +1,208 @@
+// Package source resolves Go packages and files to locations in the source tree.
+package source
+
+import (
+	"bufio"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Resolver maps import paths and file paths reported by the Go toolchain
+// to files below a source root.
+type Resolver struct {
+	root   string
+	module string
+}
+
+// Position is a line, and optionally a column, within a file.
+type Position struct {
+	// File is the path of the file on disk.
+	File string
+	// Line is the 1-based line number.
+	Line int
+	// Column is the 1-based column number, or 0 if unknown.
+	Column int
+}
+
+// NewResolver returns a Resolver for the source tree at root. The module
+// path is read from root/go.mod when present. An empty root selects the
+// working directory.
+func NewResolver(root string) *Resolver {
+	if root == "" {
+		root = "."
+	}
+	if abs, err := filepath.Abs(root); err == nil {
+		root = abs
+	}
+	return &Resolver{root: root, module: readModulePath(filepath.Join(root, "go.mod"))}
+}
+
+// Root returns the absolute source root.
+func (r *Resolver) Root() string {
+	return r.root
+}
+
+// Module returns the module path of the source root, or an empty string.
+func (r *Resolver) Module() string {
+	return r.module
+}
+
+// URI returns a URI for file: a slash-separated path relative to the root
+// when file lies below it, otherwise an absolute file URI. Relative paths
+// are interpreted relative to the root.
+func (r *Resolver) URI(file string) string {
+	abs := file
+	if !filepath.IsAbs(abs) {
+		abs = filepath.Join(r.root, file)
+	}
+	if rel, err := filepath.Rel(r.root, abs); err == nil && filepath.IsLocal(rel) {
+		return filepath.ToSlash(rel)
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String()
+}
+
+// PackageDir returns the directory of the package with the given import
+// path, if it belongs to the module at the source root.
+func (r *Resolver) PackageDir(importPath string) (string, bool) {
+	if r.module == "" {
+		return "", false
+	}
+	rel, ok := strings.CutPrefix(importPath, r.module)
+	if !ok || (rel != "" && rel[0] != '/') {
+		return "", false
+	}
+	dir := filepath.Join(r.root, filepath.FromSlash(rel))
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", false
+	}
+	return dir, true
+}
+
+// ImportPath returns the import path of the package in dir, if dir lies
+// within the module at the source root. Relative directories are
+// interpreted relative to the root.
+func (r *Resolver) ImportPath(dir string) (string, bool) {
+	if r.module == "" {
+		return "", false
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(r.root, dir)
+	}
+	rel, err := filepath.Rel(r.root, dir)
+	if err != nil || !filepath.IsLocal(rel) {
+		return "", false
+	}
+	if rel == "." {
+		return r.module, true
+	}
+	return r.module + "/" + filepath.ToSlash(rel), true
+}
+
+// FindFunc locates the declaration of the top-level function name in the
+// package with the given import path. Only _test.go files are searched
+// when tests is true, and only non-test files otherwise.
+func (r *Resolver) FindFunc(importPath, name string, tests bool) (Position, bool) {
+	var pos Position
+	found := r.inspectFuncs(importPath, tests, func(fset *token.FileSet, _ *ast.File, fn *ast.FuncDecl) bool {
+		if fn.Recv != nil || fn.Name.Name != name {
+			return false
+		}
+		p := fset.Position(fn.Name.Pos())
+		pos = Position{File: p.Filename, Line: p.Line, Column: p.Column}
+		return true
+	})
+	return pos, found
+}
+
+// outputCommentRe matches the comment that introduces the expected output
+// of an example function.
+var outputCommentRe = regexp.MustCompile(`(?i)^[[:space:]]*(unordered )?output:`)
+
+// FindExampleOutput locates the "// Output:" comment of the example
+// function name in the test files of the package. The returned positions
+// span the comment block.
+func (r *Resolver) FindExampleOutput(importPath, name string) (start, end Position, found bool) {
+	r.inspectFuncs(importPath, true, func(fset *token.FileSet, file *ast.File, fn *ast.FuncDecl) bool {
+		if fn.Recv != nil || fn.Name.Name != name || fn.Body == nil {
+			return false
+		}
+		for _, group := range file.Comments {
+			if group.Pos() < fn.Body.Lbrace || group.End() > fn.Body.Rbrace {
+				continue
+			}
+			if !outputCommentRe.MatchString(group.Text()) {
+				continue
+			}
+			s, e := fset.Position(group.Pos()), fset.Position(group.End())
+			start = Position{File: s.Filename, Line: s.Line, Column: s.Column}
+			end = Position{File: e.Filename, Line: e.Line, Column: e.Column}
+			found = true
+		}
+		return true
+	})
+	return start, end, found
+}
+
+// inspectFuncs parses the files of a package and calls fn for each function
+// declaration until it returns true. It reports whether fn returned true.
+func (r *Resolver) inspectFuncs(importPath string, tests bool, fn func(*token.FileSet, *ast.File, *ast.FuncDecl) bool) bool {
+	dir, ok := r.PackageDir(importPath)
+	if !ok {
+		return false
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") != tests {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		for _, decl := range file.Decls {
+			if fd, ok := decl.(*ast.FuncDecl); ok && fn(fset, file, fd) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readModulePath returns the module path declared in the go.mod file at
+// path, or an empty string.
+func readModulePath(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		rest, ok := strings.CutPrefix(line, "module")
+		if !ok || rest == "" || (rest[0] != ' ' && rest[0] != '\t') {
+			continue
+		}
+		rest, _, _ = strings.Cut(rest, "//")
+		rest = strings.TrimSpace(rest)
+		if unquoted, err := strconv.Unquote(rest); err == nil {
+			rest = unquoted
+		}
+		return rest
+	}
+	return ""
+}
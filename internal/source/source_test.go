@@ -0,0 +1,148 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testModule = "example.com/app"
+
+// writeModule creates a module with a single package "foo" below a
+// temporary root and returns the root.
+func writeModule(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod": "// comment\nmodule " + testModule + " // trailing\n\ngo 1.25\n",
+		"foo/foo.go": `package foo
+
+func init() {}
+
+func Foo() {}
+`,
+		"foo/foo_test.go": `package foo
+
+import "testing"
+
+func TestMain(m *testing.M) {
+	m.Run()
+}
+
+func TestFoo(t *testing.T) {}
+
+func Example_foo() {
+	// A comment that is not the output.
+	Foo()
+	// Output:
+	// foo
+	// bar
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	return root
+}
+
+func TestNewResolver_Module(t *testing.T) {
+	r := NewResolver(writeModule(t))
+	if r.Module() != testModule {
+		t.Errorf("Module() = %q, want %q", r.Module(), testModule)
+	}
+}
+
+func TestResolver_URI(t *testing.T) {
+	root := writeModule(t)
+	r := NewResolver(root)
+
+	if got := r.URI(filepath.Join(root, "foo", "foo.go")); got != "foo/foo.go" {
+		t.Errorf("URI(absolute) = %q, want %q", got, "foo/foo.go")
+	}
+	if got := r.URI("foo/foo.go"); got != "foo/foo.go" {
+		t.Errorf("URI(relative) = %q, want %q", got, "foo/foo.go")
+	}
+	if got := r.URI("/elsewhere/bar.go"); !strings.HasPrefix(got, "file:///") {
+		t.Errorf("URI(outside) = %q, want file URI", got)
+	}
+}
+
+func TestResolver_PackageDir(t *testing.T) {
+	root := writeModule(t)
+	r := NewResolver(root)
+
+	if dir, ok := r.PackageDir(testModule + "/foo"); !ok || dir != filepath.Join(root, "foo") {
+		t.Errorf("PackageDir(foo) = %q, %v", dir, ok)
+	}
+	for _, pkg := range []string{testModule + "/missing", testModule + "x/foo", "other.com/foo"} {
+		if _, ok := r.PackageDir(pkg); ok {
+			t.Errorf("PackageDir(%q) found, want not found", pkg)
+		}
+	}
+}
+
+func TestResolver_ImportPath(t *testing.T) {
+	root := writeModule(t)
+	r := NewResolver(root)
+
+	tests := []struct {
+		dir  string
+		want string
+		ok   bool
+	}{
+		{filepath.Join(root, "foo"), testModule + "/foo", true},
+		{root, testModule, true},
+		{"foo/bar", testModule + "/foo/bar", true},
+		{filepath.Dir(root), "", false},
+	}
+	for _, tt := range tests {
+		if got, ok := r.ImportPath(tt.dir); got != tt.want || ok != tt.ok {
+			t.Errorf("ImportPath(%q) = %q, %v, want %q, %v", tt.dir, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestResolver_FindFunc(t *testing.T) {
+	root := writeModule(t)
+	r := NewResolver(root)
+	pkg := testModule + "/foo"
+
+	pos, ok := r.FindFunc(pkg, "TestMain", true)
+	if !ok {
+		t.Fatal("FindFunc(TestMain) not found")
+	}
+	if pos.File != filepath.Join(root, "foo", "foo_test.go") || pos.Line != 5 {
+		t.Errorf("FindFunc(TestMain) = %s:%d, want foo_test.go:5", pos.File, pos.Line)
+	}
+
+	if pos, ok := r.FindFunc(pkg, "init", false); !ok || pos.Line != 3 {
+		t.Errorf("FindFunc(init) = %+v, %v, want line 3", pos, ok)
+	}
+	if _, ok := r.FindFunc(pkg, "Foo", true); ok {
+		t.Error("FindFunc(Foo) in test files found, want not found")
+	}
+}
+
+func TestResolver_FindExampleOutput(t *testing.T) {
+	r := NewResolver(writeModule(t))
+
+	start, end, ok := r.FindExampleOutput(testModule+"/foo", "Example_foo")
+	if !ok {
+		t.Fatal("FindExampleOutput(Example_foo) not found")
+	}
+	if start.Line != 14 || end.Line != 16 {
+		t.Errorf("FindExampleOutput() = lines %d-%d, want 14-16", start.Line, end.Line)
+	}
+
+	if _, _, ok := r.FindExampleOutput(testModule+"/foo", "TestFoo"); ok {
+		t.Error("FindExampleOutput(TestFoo) found, want not found")
+	}
+}
@@ -5,8 +5,12 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
-	"os"
+	"io"
+	"runtime"
+	"sync"
 	"time"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/compress"
 )
 
 // TestEvent captures all fields from go test -json output.
@@ -23,21 +27,67 @@ type TestEvent struct {
 	Elapsed float64 `json:"Elapsed,omitempty"`
 	// Output contains any text output from the test.
 	Output string `json:"Output,omitempty"`
+	// OutputType classifies output events; "frame" marks test framing
+	// lines such as "=== RUN" and "--- FAIL:".
+	OutputType string `json:"OutputType,omitempty"`
 	// FailedBuild indicates if this was a build failure.
 	FailedBuild bool `json:"FailedBuild,omitempty"`
+	// FailedBuildID identifies the failed build, matching the ImportPath
+	// of its build events. Go 1.24 and later report FailedBuild this way.
+	FailedBuildID string `json:"-"`
+	// ImportPath identifies the build of build-output and build-fail
+	// events, as in "example.com/foo [example.com/foo.test]".
+	ImportPath string `json:"ImportPath,omitempty"`
+	// Key is the attribute name of an attr event, set by testing.T.Attr.
+	Key string `json:"Key,omitempty"`
+	// Value is the attribute value of an attr event.
+	Value string `json:"Value,omitempty"`
+	// Path is the test's artifact directory for artifacts events.
+	Path string `json:"Path,omitempty"`
+}
+
+// UnmarshalJSON decodes an event, accepting FailedBuild both as a boolean
+// and as the build ID reported by newer toolchains.
+func (e *TestEvent) UnmarshalJSON(data []byte) error {
+	type plain TestEvent
+	aux := struct {
+		*plain
+		FailedBuild json.RawMessage `json:"FailedBuild"`
+	}{plain: (*plain)(e)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	e.FailedBuild, e.FailedBuildID = false, ""
+	if len(aux.FailedBuild) == 0 || string(aux.FailedBuild) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(aux.FailedBuild, &e.FailedBuildID); err == nil {
+		e.FailedBuild = e.FailedBuildID != ""
+		return nil
+	}
+	return json.Unmarshal(aux.FailedBuild, &e.FailedBuild)
 }
 
 // ParseFile reads and parses a go test -json output file.
+// Gzip-compressed files are decompressed transparently and the path "-"
+// reads standard input.
 // Returns an error with line number if any line contains invalid JSON.
 func ParseFile(path string) ([]TestEvent, error) {
-	f, err := os.Open(path)
+	rc, err := compress.Open(path)
 	if err != nil {
 		return nil, err
 	}
-	defer func() { _ = f.Close() }()
+	defer func() { _ = rc.Close() }()
 
+	return Parse(rc)
+}
+
+// Parse reads and parses go test -json output from r.
+// Returns an error with line number if any line contains invalid JSON.
+func Parse(r io.Reader) ([]TestEvent, error) {
 	var events []TestEvent
-	scanner := bufio.NewScanner(f)
+	scanner := bufio.NewScanner(r)
 	// Increase buffer size for large JSON lines (e.g., verbose test output)
 	// Default is 64KB; allow up to 4MB per line
 	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
@@ -58,3 +108,31 @@ func ParseFile(path string) ([]TestEvent, error) {
 
 	return events, nil
 }
+
+// ParseFiles parses several go test -json output files concurrently,
+// with at most GOMAXPROCS files open at a time. The events of paths[i]
+// are returned at index i. The first error encountered, in input order,
+// is returned prefixed with its file path.
+func ParseFiles(paths []string) ([][]TestEvent, error) {
+	events := make([][]TestEvent, len(paths))
+	errs := make([]error, len(paths))
+
+	sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+	var wg sync.WaitGroup
+	for i, path := range paths {
+		sem <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			events[i], errs[i] = ParseFile(path)
+		})
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", paths[i], err)
+		}
+	}
+
+	return events, nil
+}
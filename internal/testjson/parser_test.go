@@ -2,6 +2,7 @@
 package testjson
 
 import (
+	"compress/gzip"
 	"os"
 	"path/filepath"
 	"strings"
@@ -128,3 +129,117 @@ func TestParseFile_FileNotFound(t *testing.T) {
 		t.Fatal("expected error for nonexistent file, got nil")
 	}
 }
+
+func TestParseFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	first := filepath.Join(dir, "first.json")
+	second := filepath.Join(dir, "second.json")
+	if err := os.WriteFile(first, []byte(`{"Action":"pass","Package":"example.com/foo"}`+"\n"), 0o600); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+	if err := os.WriteFile(second, []byte(`{"Action":"fail","Package":"example.com/bar"}`+"\n"), 0o600); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	events, err := ParseFiles([]string{first, second})
+	if err != nil {
+		t.Fatalf("ParseFiles returned error: %v", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("expected events for 2 files, got %d", len(events))
+	}
+	if events[0][0].Package != testPackageName {
+		t.Errorf("events[0][0].Package = %q, want %q", events[0][0].Package, testPackageName)
+	}
+	if events[1][0].Package != "example.com/bar" {
+		t.Errorf("events[1][0].Package = %q, want %q", events[1][0].Package, "example.com/bar")
+	}
+}
+
+func TestParseFiles_ErrorNamesFile(t *testing.T) {
+	missing := "/nonexistent/path/to/file.json"
+
+	_, err := ParseFiles([]string{missing})
+	if err == nil {
+		t.Fatal("expected error for nonexistent file, got nil")
+	}
+	if !strings.Contains(err.Error(), missing) {
+		t.Errorf("error = %q, want to contain %q", err.Error(), missing)
+	}
+}
+
+func TestParseFile_Gzip(t *testing.T) {
+	dir := t.TempDir()
+	inputPath := filepath.Join(dir, "input.json.gz")
+
+	f, err := os.Create(inputPath)
+	if err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	zw := gzip.NewWriter(f)
+	if _, err := zw.Write([]byte(`{"Action":"fail","Package":"example.com/foo","Test":"TestBar"}` + "\n")); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to close gzip writer: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("failed to close test file: %v", err)
+	}
+
+	events, err := ParseFile(inputPath)
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+	if len(events) != 1 || events[0].Test != testTestName {
+		t.Errorf("events = %+v, want one %s event", events, testTestName)
+	}
+}
+
+func TestParseFile_AttrEvent(t *testing.T) {
+	dir := t.TempDir()
+	inputPath := filepath.Join(dir, testInputFile)
+
+	content := `{"Action":"attr","Package":"example.com/foo","Test":"TestBar","Key":"owner","Value":"team a"}
+`
+	if err := os.WriteFile(inputPath, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	events, err := ParseFile(inputPath)
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+
+	if events[0].Key != "owner" {
+		t.Errorf("Key = %q, want %q", events[0].Key, "owner")
+	}
+	if events[0].Value != "team a" {
+		t.Errorf("Value = %q, want %q", events[0].Value, "team a")
+	}
+}
+
+func TestParse_BuildEvents(t *testing.T) {
+	const buildID = "example.com/foo [example.com/foo.test]"
+	content := `{"ImportPath":"example.com/foo [example.com/foo.test]","Action":"build-output","Output":"# example.com/foo\n"}
+{"ImportPath":"example.com/foo [example.com/foo.test]","Action":"build-fail"}
+{"Action":"fail","Package":"example.com/foo","FailedBuild":"example.com/foo [example.com/foo.test]"}
+{"Action":"fail","Package":"example.com/bar","FailedBuild":false}
+`
+	events, err := Parse(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	if events[0].ImportPath != buildID || events[0].Output != "# example.com/foo\n" {
+		t.Errorf("build-output event = %+v", events[0])
+	}
+	if e := events[2]; !e.FailedBuild || e.FailedBuildID != buildID {
+		t.Errorf("FailedBuild, FailedBuildID = %v, %q, want true, %q", e.FailedBuild, e.FailedBuildID, buildID)
+	}
+	if e := events[3]; e.FailedBuild || e.FailedBuildID != "" {
+		t.Errorf("FailedBuild, FailedBuildID = %v, %q, want false, empty", e.FailedBuild, e.FailedBuildID)
+	}
+}
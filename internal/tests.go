@@ -0,0 +1,152 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/testjson"
+)
+
+// testCase accumulates the events of one run of a test within one input.
+// A case with an empty test name holds the package-level events.
+type testCase struct {
+	testKey
+	// Action is the latest action seen, such as run, pass or fail.
+	Action string
+	// Output holds the output lines, without test framing.
+	Output []string
+	// Frames holds the framing lines, such as "=== RUN" and "PASS".
+	Frames []string
+	// FailOutput is the output carried by the fail event itself.
+	FailOutput string
+	// Elapsed is the duration in seconds reported on completion.
+	Elapsed float64
+	// FailedBuild is set when the package failed to build.
+	FailedBuild bool
+	// BuildOutput holds the output of the failed build, as reported by
+	// build-output events.
+	BuildOutput []string
+	// Attrs are the attributes set with testing.T.Attr.
+	Attrs []attribute
+	// ArtifactDir is the artifact directory reported for the test.
+	ArtifactDir string
+}
+
+// done reports whether the test has finished.
+func (c *testCase) done() bool {
+	switch c.Action {
+	case "pass", "fail", "skip", "bench":
+		return true
+	}
+	return false
+}
+
+// outputText returns the build output and the test output, without the
+// indentation added by the testing package.
+func (c *testCase) outputText() string {
+	var b strings.Builder
+	for _, line := range c.BuildOutput {
+		b.WriteString(line)
+	}
+	for _, line := range c.Output {
+		b.WriteString(strings.TrimPrefix(line, "    "))
+	}
+	b.WriteString(c.FailOutput)
+	return strings.TrimRight(b.String(), " \t\r\n")
+}
+
+// message returns the result message text for a failed test.
+func (c *testCase) message() string {
+	if text := c.outputText(); text != "" {
+		return text
+	}
+	if c.test == "" {
+		return fmt.Sprintf("package %s failed", c.pkg)
+	}
+	return fmt.Sprintf("%s failed", c.test)
+}
+
+// collectTests groups the events of one input by test run, in order of
+// first appearance. A test that runs again after finishing, as with
+// -count, starts a new case, as does a package started again, as when
+// reruns are appended to the same file.
+func collectTests(events []testjson.TestEvent) []*testCase {
+	var cases []*testCase
+	current := make(map[testKey]*testCase)
+	builds := make(map[string][]string)
+
+	for _, e := range events {
+		switch e.Action {
+		case "build-output":
+			builds[e.ImportPath] = append(builds[e.ImportPath], e.Output)
+			continue
+		case "build-fail":
+			continue
+		}
+
+		k := testKey{pkg: e.Package, test: e.Test}
+		c := current[k]
+		if c == nil || ((e.Action == "run" || e.Action == "start") && c.done()) {
+			c = &testCase{testKey: k}
+			current[k] = c
+			cases = append(cases, c)
+		}
+
+		switch e.Action {
+		case "output":
+			if isFraming(e) {
+				c.Frames = append(c.Frames, e.Output)
+			} else {
+				c.Output = append(c.Output, e.Output)
+			}
+			continue
+		case "attr":
+			c.Attrs = append(c.Attrs, attribute{Key: e.Key, Value: e.Value})
+			continue
+		case "artifacts":
+			c.ArtifactDir = e.Path
+			continue
+		case "fail":
+			c.FailOutput += e.Output
+			c.FailedBuild = c.FailedBuild || e.FailedBuild
+			if e.FailedBuildID != "" {
+				c.BuildOutput = builds[e.FailedBuildID]
+			}
+		}
+		if e.Action != "start" {
+			c.Action = e.Action
+		}
+		if e.Elapsed != 0 {
+			c.Elapsed = e.Elapsed
+		}
+	}
+
+	return cases
+}
+
+// framingPrefixes start the lines the testing package prints to frame the
+// output of each test.
+var framingPrefixes = []string{
+	"=== RUN", "=== PAUSE", "=== CONT", "=== NAME", "=== ATTR", "=== ARTIFACTS",
+	"--- PASS:", "--- FAIL:", "--- SKIP:",
+	"FAIL\t", "ok  \t",
+}
+
+// isFraming reports whether an output event is test framing rather than
+// output written by the test. Streams from toolchains that do not set
+// OutputType are recognized by the line prefix.
+func isFraming(e testjson.TestEvent) bool {
+	if e.OutputType == "frame" {
+		return true
+	}
+	line := strings.TrimSpace(e.Output)
+	if line == "PASS" || line == "FAIL" {
+		return true
+	}
+	for _, prefix := range framingPrefixes {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
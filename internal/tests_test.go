@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/testjson"
+)
+
+func TestCollectTests(t *testing.T) {
+	events := []testjson.TestEvent{
+		{Action: "start", Package: "example.com/foo"},
+		{Action: "run", Package: "example.com/foo", Test: "TestBar"},
+		{Action: "output", Package: "example.com/foo", Test: "TestBar", Output: "=== RUN   TestBar\n", OutputType: "frame"},
+		{Action: "output", Package: "example.com/foo", Test: "TestBar", Output: "    bar_test.go:8: boom\n"},
+		{Action: "output", Package: "example.com/foo", Test: "TestBar", Output: "--- FAIL: TestBar (0.00s)\n"},
+		{Action: "fail", Package: "example.com/foo", Test: "TestBar", Elapsed: 0.5},
+		{Action: "run", Package: "example.com/foo", Test: "TestBar"},
+		{Action: "pass", Package: "example.com/foo", Test: "TestBar"},
+		{Action: "output", Package: "example.com/foo", Output: "FAIL\n"},
+		{Action: "fail", Package: "example.com/foo", Elapsed: 1},
+	}
+
+	cases := collectTests(events)
+	if len(cases) != 3 {
+		t.Fatalf("got %d cases, want package and two runs of TestBar", len(cases))
+	}
+
+	pkg, first, second := cases[0], cases[1], cases[2]
+	if pkg.test != "" || pkg.Action != "fail" || len(pkg.Frames) != 1 {
+		t.Errorf("package case = %+v", pkg)
+	}
+	if first.Action != "fail" || first.Elapsed != 0.5 {
+		t.Errorf("first run = %s after %v, want fail after 0.5", first.Action, first.Elapsed)
+	}
+	if len(first.Output) != 1 || len(first.Frames) != 2 {
+		t.Errorf("first run output = %q, frames = %q", first.Output, first.Frames)
+	}
+	if got := first.message(); got != "bar_test.go:8: boom" {
+		t.Errorf("message() = %q, want %q", got, "bar_test.go:8: boom")
+	}
+	if second.Action != "pass" {
+		t.Errorf("second run action = %s, want pass", second.Action)
+	}
+}
+
+func TestCollectTests_Rerun(t *testing.T) {
+	events := []testjson.TestEvent{
+		{Action: "start", Package: "example.com/foo"},
+		{Action: "output", Package: "example.com/foo", Output: "FAIL\n"},
+		{Action: "fail", Package: "example.com/foo"},
+		{Action: "start", Package: "example.com/foo"},
+		{Action: "output", Package: "example.com/foo", Output: "PASS\n"},
+		{Action: "pass", Package: "example.com/foo"},
+	}
+
+	cases := collectTests(events)
+	if len(cases) != 2 || cases[0].Action != "fail" || cases[1].Action != "pass" {
+		t.Fatalf("cases = %+v, want a failed and a passed package run", cases)
+	}
+}
+
+func TestCollectTests_BuildOutput(t *testing.T) {
+	const build = "example.com/foo [example.com/foo.test]"
+	events := []testjson.TestEvent{
+		{Action: "build-output", ImportPath: build, Output: "# example.com/foo\n"},
+		{Action: "build-output", ImportPath: build, Output: "foo.go:3:1: syntax error\n"},
+		{Action: "build-fail", ImportPath: build},
+		{Action: "start", Package: "example.com/foo"},
+		{Action: "output", Package: "example.com/foo", Output: "FAIL\texample.com/foo [build failed]\n", OutputType: "frame"},
+		{Action: "fail", Package: "example.com/foo", FailedBuild: true, FailedBuildID: build},
+	}
+
+	cases := collectTests(events)
+	if len(cases) != 1 {
+		t.Fatalf("got %d cases, want only the package", len(cases))
+	}
+	c := cases[0]
+	if !c.FailedBuild || len(c.BuildOutput) != 2 {
+		t.Errorf("package case = %+v, want the failed build output", c)
+	}
+	if got, want := c.message(), "# example.com/foo\nfoo.go:3:1: syntax error"; got != want {
+		t.Errorf("message() = %q, want %q", got, want)
+	}
+}
+
+func TestTestCase_MessageFallback(t *testing.T) {
+	c := &testCase{testKey: testKey{pkg: "example.com/foo", test: "TestBar"}}
+	if got := c.message(); got != "TestBar failed" {
+		t.Errorf("message() = %q, want %q", got, "TestBar failed")
+	}
+
+	c = &testCase{testKey: testKey{pkg: "example.com/foo"}}
+	if got := c.message(); got != "package example.com/foo failed" {
+		t.Errorf("message() = %q, want %q", got, "package example.com/foo failed")
+	}
+}
+
+func TestIsFraming(t *testing.T) {
+	tests := map[string]bool{
+		"=== RUN   TestBar\n":              true,
+		"    --- FAIL: TestBar/sub (0s)\n": true,
+		"PASS\n":                           true,
+		"FAIL\texample.com/foo\t0.1s\n":    true,
+		"ok  \texample.com/foo\t0.1s\n":    true,
+		"    bar_test.go:8: boom\n":        false,
+		"panic: boom\n":                    false,
+	}
+	for output, want := range tests {
+		if got := isFraming(testjson.TestEvent{Output: output}); got != want {
+			t.Errorf("isFraming(%q) = %v, want %v", output, got, want)
+		}
+	}
+
+	if !isFraming(testjson.TestEvent{Output: "anything\n", OutputType: "frame"}) {
+		t.Error("isFraming() = false for OutputType frame, want true")
+	}
+}
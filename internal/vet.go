@@ -0,0 +1,252 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/buildjson"
+	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
+	"github.com/ivuorinen/go-test-sarif-action/internal/source"
+	"github.com/ivuorinen/go-test-sarif-action/internal/vetjson"
+)
+
+// vetRule is reported for go vet diagnostics, found by go test or read
+// from go vet -json output. Diagnostics of a known analyzer are reported
+// under a "go-vet/<analyzer>" sub-rule.
+var vetRule = sarif.Rule{
+	ID:          "go-vet",
+	Description: "go vet diagnostic",
+}
+
+// vetHeaderRe matches the header go vet prints before its diagnostics,
+// "# [example.com/foo]". Compiler errors are headed by the bare package
+// path instead.
+var vetHeaderRe = regexp.MustCompile(`^# \[(\S+)\]$`)
+
+// vetAnalyzers identifies the analyzers run by go test by the text of
+// their diagnostics.
+var vetAnalyzers = []struct {
+	name string
+	re   *regexp.Regexp
+}{
+	{"printf", regexp.MustCompile(`format %|formatting directive|call needs \d+ args? but has|call has arguments but no formatting directives|ends with redundant newline|non-constant format string`)},
+	{"tests", regexp.MustCompile(`refers to unknown |has malformed |should return nothing|should be niladic`)},
+	{"atomic", regexp.MustCompile(`direct assignment to atomic value`)},
+	{"bools", regexp.MustCompile(`^(redundant|suspect) (and|or):`)},
+	{"buildtag", regexp.MustCompile(`build constraint|//go:build|\+build`)},
+	{"directive", regexp.MustCompile(`directive`)},
+	{"errorsas", regexp.MustCompile(`second argument to errors\.As`)},
+	{"ifaceassert", regexp.MustCompile(`impossible type assertion`)},
+	{"nilfunc", regexp.MustCompile(`comparison of function .* is always`)},
+	{"stringintconv", regexp.MustCompile(`conversion from .* to string yields a string of one rune`)},
+}
+
+// vetDiagnostic is one finding of go vet.
+type vetDiagnostic struct {
+	// File is the source file as printed, relative to the directory go
+	// test ran in.
+	File string
+	// Line and Column locate the finding; Column is 0 if not printed.
+	Line, Column int
+	// Message is the diagnostic text.
+	Message string
+	// Analyzer names the analyzer that reported it, if identifiable.
+	Analyzer string
+}
+
+// parseVetOutput extracts the go vet diagnostics from the output of a
+// failed build. It reports false if the output holds no vet findings,
+// as when the build failed to compile.
+func parseVetOutput(output []string) ([]vetDiagnostic, bool) {
+	var vet []string
+	inVet := false
+	for _, line := range output {
+		if strings.HasPrefix(line, "# ") {
+			inVet = vetHeaderRe.MatchString(strings.TrimRight(line, "\r\n"))
+		} else if inVet {
+			vet = append(vet, line)
+		}
+	}
+
+	var diags []vetDiagnostic
+	for _, d := range buildjson.Diagnostics(vet) {
+		diags = append(diags, vetDiagnostic{
+			File:     d.File,
+			Line:     d.Line,
+			Column:   d.Column,
+			Message:  d.Message,
+			Analyzer: vetAnalyzer(d.Message),
+		})
+	}
+	return diags, len(diags) > 0
+}
+
+// vetAnalyzer returns the analyzer that reports msg, or "" if unknown.
+func vetAnalyzer(msg string) string {
+	for _, a := range vetAnalyzers {
+		if a.re.MatchString(msg) {
+			return a.name
+		}
+	}
+	return ""
+}
+
+// vetAnalyzerDocs describes the analyzers of go vet, as listed by
+// "go tool vet help".
+var vetAnalyzerDocs = map[string]string{
+	"appends":          "check for missing values after append",
+	"asmdecl":          "report mismatches between assembly files and Go declarations",
+	"assign":           "check for useless assignments",
+	"atomic":           "check for common mistakes using the sync/atomic package",
+	"bools":            "check for common mistakes involving boolean operators",
+	"buildtag":         "check //go:build and // +build directives",
+	"cgocall":          "detect some violations of the cgo pointer passing rules",
+	"composites":       "check for unkeyed composite literals",
+	"copylocks":        "check for locks erroneously passed by value",
+	"defers":           "report common mistakes in defer statements",
+	"directive":        "check Go toolchain directives such as //go:debug",
+	"errorsas":         "report passing non-pointer or non-error values to errors.As",
+	"framepointer":     "report assembly that clobbers the frame pointer before saving it",
+	"hostport":         "check format of addresses passed to net.Dial",
+	"httpresponse":     "check for mistakes using HTTP responses",
+	"ifaceassert":      "detect impossible interface-to-interface type assertions",
+	"loopclosure":      "check references to loop variables from within nested functions",
+	"lostcancel":       "check cancel func returned by context.WithCancel is called",
+	"nilfunc":          "check for useless comparisons between functions and nil",
+	"printf":           "check consistency of Printf format strings and arguments",
+	"shift":            "check for shifts that equal or exceed the width of the integer",
+	"sigchanyzer":      "check for unbuffered channel of os.Signal",
+	"slog":             "check for invalid structured logging calls",
+	"stdmethods":       "check signature of methods of well-known interfaces",
+	"stdversion":       "report uses of too-new standard library symbols",
+	"stringintconv":    "check for string(int) conversions",
+	"structtag":        "check that struct field tags conform to reflect.StructTag.Get",
+	"testinggoroutine": "report calls to (*testing.T).Fatal from goroutines started by a test",
+	"tests":            "check for common mistaken usages of tests and examples",
+	"timeformat":       "check for calls of (time.Time).Format or time.Parse with 2006-02-01",
+	"unmarshal":        "report passing non-pointer or non-interface values to unmarshal",
+	"unreachable":      "check for unreachable code",
+	"unsafeptr":        "check for invalid conversions of uintptr to unsafe.Pointer",
+	"unusedresult":     "check for unused results of calls to some functions",
+	"waitgroup":        "check for misuses of sync.WaitGroup",
+}
+
+// vetPassPackages names the analysis pass packages whose name differs
+// from the analyzer.
+var vetPassPackages = map[string]string{
+	"composites": "composite",
+	"copylocks":  "copylock",
+}
+
+// vetAnalyzerRule returns the rule of diagnostics of analyzer. Analyzers
+// of go vet link to their documentation.
+func vetAnalyzerRule(analyzer string) sarif.Rule {
+	if analyzer == "" {
+		return vetRule
+	}
+	rule := sarif.Rule{
+		ID:          vetRule.ID + "/" + analyzer,
+		Description: "go vet " + analyzer + " analyzer",
+	}
+	if doc, ok := vetAnalyzerDocs[analyzer]; ok {
+		rule.Description = doc
+		pass := analyzer
+		if p, ok := vetPassPackages[analyzer]; ok {
+			pass = p
+		}
+		rule.HelpURI = "https://pkg.go.dev/golang.org/x/tools/go/analysis/passes/" + pass
+	}
+	return rule
+}
+
+// vetResult returns the result of a vet diagnostic in pkg.
+func vetResult(d vetDiagnostic, pkg string, resolver *source.Resolver) sarif.Result {
+	result := sarif.Result{
+		RuleID:   vetAnalyzerRule(d.Analyzer).ID,
+		Level:    "error",
+		Message:  d.Message,
+		Location: &sarif.LogicalLocation{Module: pkg},
+		PhysicalLocation: &sarif.PhysicalLocation{
+			URI:         resolver.URI(d.File),
+			StartLine:   d.Line,
+			StartColumn: d.Column,
+		},
+		Properties: map[string]any{},
+	}
+	if d.Analyzer != "" {
+		result.Properties["analyzer"] = d.Analyzer
+	}
+	return result
+}
+
+// addVetJSONResults adds the findings of go vet -json output to the
+// report, one result per diagnostic or analyzer error.
+func addVetJSONResults(b *reportBuilder, in input, resolver *source.Resolver) {
+	for _, pkg := range in.Vet {
+		for _, a := range pkg.Analyzers {
+			rule := vetAnalyzerRule(a.Name)
+			b.addRule(rule)
+
+			if a.Error != "" {
+				b.add(sarif.Result{
+					RuleID:     rule.ID,
+					Level:      "error",
+					Message:    fmt.Sprintf("%s analyzer failed: %s", a.Name, a.Error),
+					Location:   &sarif.LogicalLocation{Module: pkg.ImportPath},
+					Properties: map[string]any{"analyzer": a.Name},
+				}, in.Path)
+			}
+			for _, d := range a.Diagnostics {
+				b.add(vetJSONResult(d, rule, a.Name, pkg.ImportPath, resolver), in.Path)
+			}
+		}
+	}
+}
+
+// vetJSONResult returns the result of a go vet -json diagnostic, with its
+// suggested fixes.
+func vetJSONResult(d vetjson.Diagnostic, rule sarif.Rule, analyzer, pkg string, resolver *source.Resolver) sarif.Result {
+	result := sarif.Result{
+		RuleID:     rule.ID,
+		Level:      "error",
+		Message:    d.Message,
+		Location:   &sarif.LogicalLocation{Module: pkg},
+		Properties: map[string]any{"analyzer": analyzer},
+	}
+	if d.Category != "" {
+		result.Properties["category"] = d.Category
+	}
+
+	if start, ok := vetjson.ParsePosition(d.Posn); ok {
+		loc := &sarif.PhysicalLocation{
+			URI:         resolver.URI(start.File),
+			StartLine:   start.Line,
+			StartColumn: start.Column,
+		}
+		if end, ok := vetjson.ParsePosition(d.End); ok && end.File == start.File {
+			loc.EndLine, loc.EndColumn = end.Line, end.Column
+		}
+		result.PhysicalLocation = loc
+	}
+
+	for _, sf := range d.SuggestedFixes {
+		fix := sarif.Fix{Description: sf.Message}
+		for _, edit := range sf.Edits {
+			uri := resolver.URI(edit.Filename)
+			i := slices.IndexFunc(fix.Changes, func(c sarif.ArtifactChange) bool { return c.URI == uri })
+			if i < 0 {
+				i = len(fix.Changes)
+				fix.Changes = append(fix.Changes, sarif.ArtifactChange{URI: uri})
+			}
+			fix.Changes[i].Replacements = append(fix.Changes[i].Replacements, sarif.Replacement{
+				ByteOffset:   edit.Start,
+				ByteLength:   edit.End - edit.Start,
+				InsertedText: edit.New,
+			})
+		}
+		result.Fixes = append(result.Fixes, fix)
+	}
+	return result
+}
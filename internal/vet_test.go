@@ -0,0 +1,170 @@
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ivuorinen/go-test-sarif-action/internal/sarif"
+	"github.com/ivuorinen/go-test-sarif-action/internal/testjson"
+	"github.com/ivuorinen/go-test-sarif-action/internal/vetjson"
+)
+
+func TestParseVetOutput(t *testing.T) {
+	output := []string{
+		"# example.com/app/foo\n",
+		"# [example.com/app/foo]\n",
+		"foo/foo.go:6:22: fmt.Sprintf format %d has arg \"x\" of wrong type string\n",
+		"foo/foo_test.go:5:1: ExampleHello refers to unknown identifier: Hello\n",
+		"foo/foo.go:9: possible misuse of something\n",
+		"\tsecond line\n",
+	}
+
+	diags, ok := parseVetOutput(output)
+	if !ok || len(diags) != 3 {
+		t.Fatalf("parseVetOutput() = %+v, %v, want 3 diagnostics", diags, ok)
+	}
+	want := []vetDiagnostic{
+		{File: "foo/foo.go", Line: 6, Column: 22, Message: `fmt.Sprintf format %d has arg "x" of wrong type string`, Analyzer: "printf"},
+		{File: "foo/foo_test.go", Line: 5, Column: 1, Message: "ExampleHello refers to unknown identifier: Hello", Analyzer: "tests"},
+		{File: "foo/foo.go", Line: 9, Message: "possible misuse of something\nsecond line"},
+	}
+	for i := range want {
+		if diags[i] != want[i] {
+			t.Errorf("diagnostic %d = %+v, want %+v", i, diags[i], want[i])
+		}
+	}
+}
+
+func TestParseVetOutput_CompileError(t *testing.T) {
+	output := []string{
+		"# example.com/app/foo [example.com/app/foo.test]\n",
+		"foo/foo.go:4:9: cannot use \"x\" (untyped string constant) as int value in return statement\n",
+	}
+	if diags, ok := parseVetOutput(output); ok {
+		t.Errorf("parseVetOutput() = %+v, want compiler errors ignored", diags)
+	}
+}
+
+func TestVetAnalyzer(t *testing.T) {
+	tests := map[string]string{
+		"fmt.Println call has possible Printf formatting directive %d":      "printf",
+		"direct assignment to atomic value":                                 "atomic",
+		"redundant or: x == 1 || x == 1":                                    "bools",
+		"second argument to errors.As must be a non-nil pointer":            "errorsas",
+		"impossible type assertion: r.(io.Writer)":                          "ifaceassert",
+		"comparison of function F != nil is always true":                    "nilfunc",
+		"conversion from int to string yields a string of one rune":         "stringintconv",
+		"TestFoo has malformed name: first letter after 'Test' must not be": "tests",
+		"something else entirely":                                           "",
+	}
+	for msg, want := range tests {
+		if got := vetAnalyzer(msg); got != want {
+			t.Errorf("vetAnalyzer(%q) = %q, want %q", msg, got, want)
+		}
+	}
+}
+
+func TestBuildReport_Vet(t *testing.T) {
+	const build = "example.com/app/foo [example.com/app/foo.test]"
+	events := []testjson.TestEvent{
+		{Action: "build-output", ImportPath: build, Output: "# example.com/app/foo\n"},
+		{Action: "build-output", ImportPath: build, Output: "# [example.com/app/foo]\n"},
+		{Action: "build-output", ImportPath: build, Output: "foo/foo.go:6:22: fmt.Sprintf format %d has arg \"x\" of wrong type string\n"},
+		{Action: "build-output", ImportPath: build, Output: "foo/foo.go:8:2: unknown vet finding\n"},
+		{Action: "build-fail", ImportPath: build},
+		{Action: "fail", Package: testPkg, FailedBuild: true, FailedBuildID: build},
+	}
+
+	report := buildReport([]input{{Path: "in.json", Events: events}}, ConvertOptions{SourceRoot: t.TempDir()})
+
+	if len(report.Results) != 2 {
+		t.Fatalf("got %d results, want one per diagnostic: %+v", len(report.Results), report.Results)
+	}
+	r := report.Results[0]
+	if r.RuleID != "go-vet/printf" || r.Properties["analyzer"] != "printf" {
+		t.Errorf("result = %s %v, want go-vet/printf", r.RuleID, r.Properties)
+	}
+	if loc := r.PhysicalLocation; loc == nil || loc.URI != "foo/foo.go" || loc.StartLine != 6 || loc.StartColumn != 22 {
+		t.Errorf("PhysicalLocation = %+v, want foo/foo.go:6:22", loc)
+	}
+	if got := report.Results[1].RuleID; got != vetRule.ID {
+		t.Errorf("RuleID = %q, want %q for an unknown analyzer", got, vetRule.ID)
+	}
+
+	var ids []string
+	for _, rule := range report.Rules {
+		ids = append(ids, rule.ID)
+	}
+	if len(ids) != 3 {
+		t.Errorf("rules = %v, want go-test-failure, go-vet/printf and go-vet", ids)
+	}
+}
+
+func TestAddVetJSONResults(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, "foo", "foo.go")
+	in := input{Path: "vet.json", Format: formatVetJSON, Vet: []vetjson.Package{{
+		ImportPath: testPkg,
+		Analyzers: []vetjson.Analyzer{
+			{Name: "custom", Error: "boom"},
+			{Name: "printf", Diagnostics: []vetjson.Diagnostic{{
+				Posn:    file + ":12:21",
+				End:     file + ":12:22",
+				Message: "non-constant format string in call to fmt.Sprintf",
+				SuggestedFixes: []vetjson.SuggestedFix{{
+					Message: `Insert "%s" format string`,
+					Edits: []vetjson.TextEdit{
+						{Filename: file, Start: 124, End: 124, New: `"%s", `},
+						{Filename: file, Start: 130, End: 131},
+					},
+				}},
+			}}},
+		},
+	}}}
+
+	report := buildReport([]input{in}, ConvertOptions{SourceRoot: root})
+
+	if len(report.Results) != 2 {
+		t.Fatalf("got %d results, want 2: %+v", len(report.Results), report.Results)
+	}
+	if r := report.Results[0]; r.RuleID != "go-vet/custom" || r.Level != "error" {
+		t.Errorf("analyzer error result = %+v", r)
+	}
+
+	r := report.Results[1]
+	if r.RuleID != "go-vet/printf" || r.Level != "error" {
+		t.Errorf("result = %s %s, want a go-vet/printf error", r.RuleID, r.Level)
+	}
+	want := sarif.PhysicalLocation{URI: "foo/foo.go", StartLine: 12, StartColumn: 21, EndLine: 12, EndColumn: 22}
+	if r.PhysicalLocation == nil || *r.PhysicalLocation != want {
+		t.Errorf("PhysicalLocation = %+v, want %+v", r.PhysicalLocation, want)
+	}
+	if len(r.Fixes) != 1 || len(r.Fixes[0].Changes) != 1 {
+		t.Fatalf("Fixes = %+v, want one change", r.Fixes)
+	}
+	change := r.Fixes[0].Changes[0]
+	if change.URI != "foo/foo.go" || len(change.Replacements) != 2 {
+		t.Fatalf("change = %+v, want two replacements in foo/foo.go", change)
+	}
+	if rep := change.Replacements[1]; rep.ByteOffset != 130 || rep.ByteLength != 1 || rep.InsertedText != "" {
+		t.Errorf("replacement = %+v, want a deletion of one byte", rep)
+	}
+
+	for _, rule := range report.Rules {
+		if rule.ID == "go-vet/printf" && rule.HelpURI != "https://pkg.go.dev/golang.org/x/tools/go/analysis/passes/printf" {
+			t.Errorf("printf rule = %+v, want a help link", rule)
+		}
+	}
+}
+
+func TestVetAnalyzerRule(t *testing.T) {
+	if r := vetAnalyzerRule("copylocks"); r.ID != "go-vet/copylocks" || r.HelpURI != "https://pkg.go.dev/golang.org/x/tools/go/analysis/passes/copylock" {
+		t.Errorf("vetAnalyzerRule(copylocks) = %+v", r)
+	}
+	if r := vetAnalyzerRule("custom"); r.HelpURI != "" || r.Description != "go vet custom analyzer" {
+		t.Errorf("vetAnalyzerRule(custom) = %+v", r)
+	}
+	if r := vetAnalyzerRule(""); r.ID != vetRule.ID {
+		t.Errorf("vetAnalyzerRule(\"\") = %+v, want the go-vet rule", r)
+	}
+}
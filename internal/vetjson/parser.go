@@ -0,0 +1,170 @@
+// Package vetjson provides parsing utilities for go vet -json output.
+package vetjson
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+// Package holds the findings of the analyzers run on one package.
+type Package struct {
+	// ImportPath is the path of the analyzed package.
+	ImportPath string
+	// Analyzers are the analyzers that reported findings, sorted by name.
+	Analyzers []Analyzer
+}
+
+// Analyzer holds the findings of one analyzer in a package.
+type Analyzer struct {
+	// Name is the analyzer name, such as "printf".
+	Name string
+	// Diagnostics are the findings, in the order reported.
+	Diagnostics []Diagnostic
+	// Error is set when the analyzer failed instead of reporting findings.
+	Error string
+}
+
+// Diagnostic is a single finding of an analyzer.
+type Diagnostic struct {
+	// Category optionally classifies the finding within the analyzer.
+	Category string `json:"category,omitempty"`
+	// Posn is the start of the finding, as "file:line:col".
+	Posn string `json:"posn"`
+	// End is the end of the finding, as "file:line:col", if known.
+	End string `json:"end,omitempty"`
+	// Message describes the finding.
+	Message string `json:"message"`
+	// SuggestedFixes are alternative edits that resolve the finding.
+	SuggestedFixes []SuggestedFix `json:"suggested_fixes,omitempty"`
+	// Related are other positions relevant to the finding.
+	Related []Related `json:"related,omitempty"`
+}
+
+// SuggestedFix is a set of edits that resolves a finding.
+type SuggestedFix struct {
+	// Message describes the fix.
+	Message string `json:"message"`
+	// Edits are the changes to make.
+	Edits []TextEdit `json:"edits"`
+}
+
+// TextEdit replaces the bytes [Start, End) of a file with New.
+type TextEdit struct {
+	// Filename is the file to edit.
+	Filename string `json:"filename"`
+	// Start is the 0-based byte offset of the replaced text.
+	Start int `json:"start"`
+	// End is the byte offset after the replaced text.
+	End int `json:"end"`
+	// New is the replacement text.
+	New string `json:"new"`
+}
+
+// Related is a position related to a finding.
+type Related struct {
+	// Posn is the start of the related position, as "file:line:col".
+	Posn string `json:"posn"`
+	// End is the end of the related position, if known.
+	End string `json:"end,omitempty"`
+	// Message describes the relation.
+	Message string `json:"message"`
+}
+
+// Position is a parsed "file:line:col" position.
+type Position struct {
+	// File is the source file path.
+	File string
+	// Line is the 1-based line.
+	Line int
+	// Column is the 1-based column, or 0 if not given.
+	Column int
+}
+
+var posnRe = regexp.MustCompile(`^(.+?):(\d+)(?::(\d+))?$`)
+
+// ParsePosition parses a "file:line:col" or "file:line" position.
+func ParsePosition(posn string) (Position, bool) {
+	m := posnRe.FindStringSubmatch(posn)
+	if m == nil {
+		return Position{}, false
+	}
+	p := Position{File: m[1]}
+	p.Line, _ = strconv.Atoi(m[2])
+	p.Column, _ = strconv.Atoi(m[3])
+	return p, true
+}
+
+// Parse reads and parses go vet -json output from r. The output is a
+// sequence of JSON objects, one per package; "#" comment lines that the
+// go command prints between them are skipped.
+func Parse(r io.Reader) ([]Package, error) {
+	var buf bytes.Buffer
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		if line := scanner.Bytes(); !bytes.HasPrefix(line, []byte("#")) {
+			buf.Write(line)
+			buf.WriteByte('\n')
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var pkgs []Package
+	dec := json.NewDecoder(&buf)
+	for {
+		var tree map[string]map[string]json.RawMessage
+		if err := dec.Decode(&tree); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+
+		for path, analyzers := range tree {
+			pkg := Package{ImportPath: path}
+			for name, raw := range analyzers {
+				a, err := parseAnalyzer(name, raw)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %s: %w", path, name, err)
+				}
+				pkg.Analyzers = append(pkg.Analyzers, a)
+			}
+			slices.SortFunc(pkg.Analyzers, func(a, b Analyzer) int {
+				return cmp.Compare(a.Name, b.Name)
+			})
+			pkgs = append(pkgs, pkg)
+		}
+	}
+
+	slices.SortStableFunc(pkgs, func(a, b Package) int {
+		return cmp.Compare(a.ImportPath, b.ImportPath)
+	})
+	return pkgs, nil
+}
+
+// parseAnalyzer decodes the findings of an analyzer, which are either a
+// list of diagnostics or an {"error": ...} object.
+func parseAnalyzer(name string, raw json.RawMessage) (Analyzer, error) {
+	a := Analyzer{Name: name}
+	if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{")) {
+		var failure struct {
+			Error string `json:"error"`
+		}
+		if err := json.Unmarshal(raw, &failure); err != nil {
+			return a, err
+		}
+		a.Error = failure.Error
+		return a, nil
+	}
+	err := json.Unmarshal(raw, &a.Diagnostics)
+	return a, err
+}
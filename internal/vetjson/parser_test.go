@@ -0,0 +1,94 @@
+package vetjson
+
+import (
+	"strings"
+	"testing"
+)
+
+const vetOutput = `# example.com/foo
+{
+	"example.com/foo": {
+		"printf": [
+			{
+				"posn": "/src/foo/foo.go:12:21",
+				"end": "/src/foo/foo.go:12:22",
+				"message": "non-constant format string in call to fmt.Sprintf",
+				"suggested_fixes": [
+					{
+						"message": "Insert \"%s\" format string",
+						"edits": [
+							{"filename": "/src/foo/foo.go", "start": 124, "end": 124, "new": "\"%s\", "}
+						]
+					}
+				]
+			}
+		],
+		"copylocks": [
+			{
+				"posn": "/src/foo/foo.go:10:10",
+				"message": "F passes lock by value"
+			}
+		],
+		"buildssa": {"error": "internal error"}
+	}
+}
+{}
+{"example.com/bar": {}}
+`
+
+func TestParse(t *testing.T) {
+	pkgs, err := Parse(strings.NewReader(vetOutput))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(pkgs) != 2 || pkgs[0].ImportPath != "example.com/bar" || pkgs[1].ImportPath != "example.com/foo" {
+		t.Fatalf("packages = %+v, want bar and foo", pkgs)
+	}
+
+	analyzers := pkgs[1].Analyzers
+	if len(analyzers) != 3 {
+		t.Fatalf("analyzers = %+v, want 3", analyzers)
+	}
+	if a := analyzers[0]; a.Name != "buildssa" || a.Error != "internal error" {
+		t.Errorf("analyzer = %+v, want a buildssa error", a)
+	}
+	if a := analyzers[1]; a.Name != "copylocks" || len(a.Diagnostics) != 1 {
+		t.Errorf("analyzer = %+v, want copylocks with one diagnostic", a)
+	}
+
+	d := analyzers[2].Diagnostics[0]
+	if d.Posn != "/src/foo/foo.go:12:21" || d.End != "/src/foo/foo.go:12:22" {
+		t.Errorf("diagnostic = %+v", d)
+	}
+	if len(d.SuggestedFixes) != 1 || len(d.SuggestedFixes[0].Edits) != 1 {
+		t.Fatalf("suggested fixes = %+v", d.SuggestedFixes)
+	}
+	if e := d.SuggestedFixes[0].Edits[0]; e.Filename != "/src/foo/foo.go" || e.Start != 124 || e.End != 124 || e.New != `"%s", ` {
+		t.Errorf("edit = %+v", e)
+	}
+}
+
+func TestParse_MalformedJSON(t *testing.T) {
+	if _, err := Parse(strings.NewReader(`{"example.com/foo": [1]}`)); err == nil {
+		t.Error("Parse accepted a malformed tree")
+	}
+}
+
+func TestParsePosition(t *testing.T) {
+	tests := []struct {
+		posn string
+		want Position
+		ok   bool
+	}{
+		{"/src/foo.go:12:21", Position{File: "/src/foo.go", Line: 12, Column: 21}, true},
+		{`C:\src\foo.go:3:1`, Position{File: `C:\src\foo.go`, Line: 3, Column: 1}, true},
+		{"foo.go:7", Position{File: "foo.go", Line: 7}, true},
+		{"-", Position{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParsePosition(tt.posn)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParsePosition(%q) = %+v, %v, want %+v, %v", tt.posn, got, ok, tt.want, tt.ok)
+		}
+	}
+}